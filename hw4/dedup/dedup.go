@@ -0,0 +1,52 @@
+package dedup
+
+type Policy int
+
+const (
+	// KeepFirst keeps the first item with a key and reports the later ones as duplicates.
+	KeepFirst Policy = iota
+	// KeepLast keeps the last item with a key and reports the earlier ones as duplicates.
+	KeepLast
+	// Merge folds every duplicate into the kept item with the merge function.
+	Merge
+)
+
+type Result[T any] struct {
+	Unique     []T
+	Duplicates []T
+}
+
+// By removes items with the same key. Unique items stay in the order their key
+// first appeared, whatever the policy. merge is only used by the Merge policy
+// and receives the item kept so far and the duplicate; it must not be nil then.
+func By[T any, K comparable](items []T, key func(T) K, policy Policy, merge func(kept, duplicate T) T) Result[T] {
+	positions := make(map[K]int)
+	var result Result[T]
+
+	for _, item := range items {
+		k := key(item)
+		pos, exists := positions[k]
+		if !exists {
+			positions[k] = len(result.Unique)
+			result.Unique = append(result.Unique, item)
+			continue
+		}
+
+		switch policy {
+		case KeepLast:
+			result.Duplicates = append(result.Duplicates, result.Unique[pos])
+			result.Unique[pos] = item
+		case Merge:
+			result.Duplicates = append(result.Duplicates, item)
+			result.Unique[pos] = merge(result.Unique[pos], item)
+		default:
+			result.Duplicates = append(result.Duplicates, item)
+		}
+	}
+	return result
+}
+
+// Unique keeps the first item for every key.
+func Unique[T any, K comparable](items []T, key func(T) K) Result[T] {
+	return By(items, key, KeepFirst, nil)
+}
@@ -0,0 +1,36 @@
+package dedup
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Compare returns a negative number when a goes before b, a positive number
+// when b goes before a and zero when their order doesn't matter.
+type Compare[T any] func(a, b T) int
+
+func Asc[T any, K cmp.Ordered](key func(T) K) Compare[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+func Desc[T any, K cmp.Ordered](key func(T) K) Compare[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(b), key(a))
+	}
+}
+
+// SortBy sorts items in place by the first compare, then by the next one for
+// ties and so on. Items equal by every compare keep their original order.
+func SortBy[T any](items []T, compares ...Compare[T]) []T {
+	slices.SortStableFunc(items, func(a, b T) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return items
+}
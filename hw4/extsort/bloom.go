@@ -0,0 +1,63 @@
+package extsort
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64
+	hashes int
+}
+
+// newBloomFilter sizes the filter so that after expectedItems insertions the
+// chance of a false positive stays around falsePositiveRate.
+func newBloomFilter(expectedItems uint64, falsePositiveRate float64) *bloomFilter {
+	if expectedItems == 0 {
+		expectedItems = 1
+	}
+	m := uint64(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	hashes := int(math.Round(float64(m) / float64(expectedItems) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: hashes,
+	}
+}
+
+func (b *bloomFilter) add(id int64) {
+	h1, h2 := b.hash(id)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (b *bloomFilter) mayContain(id int64) bool {
+	h1, h2 := b.hash(id)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hash splits one 64-bit FNV hash into the two halves used for double hashing.
+func (b *bloomFilter) hash(id int64) (uint64, uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(id))
+	h := fnv.New64a()
+	h.Write(buf[:])
+	sum := h.Sum64()
+	return sum & math.MaxUint32, sum>>32 | 1
+}
@@ -0,0 +1,431 @@
+package extsort
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrNotSeekable = errors.New("bloom pre-pass needs an input that can be read twice")
+
+type Options struct {
+	// ChunkSize is how many IDs are sorted in memory before they are spilled to disk.
+	ChunkSize int
+	// TempDir is where the spill directory is created. Empty means os.TempDir().
+	TempDir string
+	// Bloom enables a first pass that finds the IDs which may repeat, so
+	// duplicates are dropped before they are ever spilled.
+	Bloom bool
+	// ExpectedItems sizes the Bloom filter. 0 estimates it from the size of
+	// the input and the length of its first lines.
+	ExpectedItems     uint64
+	FalsePositiveRate float64
+	// MaxCandidates caps the possible repeats kept in memory. Past it, or when
+	// more than ExpectedItems IDs reach the filter, the pre-pass is given up
+	// and duplicates are only dropped while merging. 0 means ChunkSize.
+	MaxCandidates int
+	// MergeFanIn is how many spill files are merged, and so kept open, at a
+	// time. More files are merged in several passes. 0 means 64.
+	MergeFanIn int
+}
+
+type Stats struct {
+	Read       int64
+	Unique     int64
+	Duplicates int64
+	SpillFiles int
+	// Candidates is the number of IDs the Bloom filter flagged as possible repeats.
+	Candidates int
+	// ExpectedItems is what the Bloom filter was sized for.
+	ExpectedItems uint64
+	// BloomSkipped is set when the pre-pass was given up because the filter
+	// was too small or there were more than MaxCandidates candidates.
+	BloomSkipped bool
+	// MergePasses is how many times the IDs were merged, 1 when every spill
+	// file fit in one merge.
+	MergePasses int
+}
+
+// UniqueSorted reads one ID per line from r and writes every distinct ID once,
+// in ascending order and one per line, to w. Memory use is bounded by
+// ChunkSize, plus the Bloom filter and at most MaxCandidates candidates when
+// Bloom is set.
+func UniqueSorted(r io.Reader, w io.Writer, opts Options) (Stats, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1 << 20
+	}
+	if opts.FalsePositiveRate <= 0 || opts.FalsePositiveRate >= 1 {
+		opts.FalsePositiveRate = 0.01
+	}
+	if opts.MaxCandidates <= 0 {
+		opts.MaxCandidates = opts.ChunkSize
+	}
+	if opts.MergeFanIn <= 0 {
+		opts.MergeFanIn = 64
+	}
+	opts.MergeFanIn = max(opts.MergeFanIn, 2)
+
+	dir, err := os.MkdirTemp(opts.TempDir, "extsort-")
+	if err != nil {
+		return Stats{}, fmt.Errorf("creating spill directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var stats Stats
+	s := &spiller{dir: dir, chunk: make([]int64, 0, opts.ChunkSize)}
+
+	var candidates map[int64]bool
+	if opts.Bloom {
+		candidates, err = findCandidates(r, &opts)
+		if err != nil {
+			return stats, err
+		}
+		stats.Candidates = len(candidates)
+		stats.ExpectedItems = opts.ExpectedItems
+		stats.BloomSkipped = candidates == nil
+	}
+
+	if candidates != nil {
+		// candidates[id] turns true once the ID has been passed on, so every
+		// later copy is a duplicate. IDs outside candidates appear only once.
+		err = readIDs(r, func(id int64) error {
+			stats.Read++
+			if written, ok := candidates[id]; ok {
+				if written {
+					return nil
+				}
+				candidates[id] = true
+			}
+			return s.add(id)
+		})
+		if err != nil {
+			return stats, err
+		}
+	} else {
+		err = readIDs(r, func(id int64) error {
+			stats.Read++
+			return s.add(id)
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+	if err := s.flush(); err != nil {
+		return stats, err
+	}
+	stats.SpillFiles = len(s.files)
+
+	stats.Unique, stats.MergePasses, err = merge(s, w, opts.MergeFanIn)
+	if err != nil {
+		return stats, err
+	}
+	stats.Duplicates = stats.Read - stats.Unique
+	return stats, nil
+}
+
+// errGiveUp stops the pre-pass once it can no longer keep memory bounded.
+var errGiveUp = errors.New("bloom pre-pass given up")
+
+// findCandidates reads the input once and collects every ID the Bloom filter
+// has possibly seen before, then rewinds the input for the second pass. It
+// returns nil candidates when the pre-pass was given up.
+func findCandidates(r io.Reader, opts *Options) (map[int64]bool, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return nil, ErrNotSeekable
+	}
+	if opts.ExpectedItems == 0 {
+		expected, err := estimateItems(seeker, r)
+		if err != nil {
+			return nil, err
+		}
+		opts.ExpectedItems = expected
+	}
+	filter := newBloomFilter(opts.ExpectedItems, opts.FalsePositiveRate)
+	candidates := make(map[int64]bool)
+	var added uint64
+
+	err := readIDs(r, func(id int64) error {
+		if filter.mayContain(id) {
+			candidates[id] = false
+			if len(candidates) > opts.MaxCandidates {
+				return errGiveUp
+			}
+			return nil
+		}
+		filter.add(id)
+		added++
+		// Past its expected size the filter fills up and flags almost
+		// every ID, so it is no use anymore.
+		if added > opts.ExpectedItems {
+			return errGiveUp
+		}
+		return nil
+	})
+	if errors.Is(err, errGiveUp) {
+		candidates, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewinding input: %w", err)
+	}
+	return candidates, nil
+}
+
+// sampleSize is how much of the input estimateItems reads to learn the
+// average line length.
+const sampleSize = 64 << 10
+
+// estimateItems guesses the number of IDs from the size of the input and the
+// average length of the lines at its start, with 10% to spare.
+func estimateItems(seeker io.Seeker, r io.Reader) (uint64, error) {
+	size, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("measuring input: %w", err)
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("rewinding input: %w", err)
+	}
+	sample := make([]byte, min(size, sampleSize))
+	n, err := io.ReadFull(r, sample)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, fmt.Errorf("reading ids: %w", err)
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("rewinding input: %w", err)
+	}
+
+	lines := max(bytes.Count(sample[:n], []byte{'\n'}), 1)
+	lineLength := max(float64(n)/float64(lines), 1)
+	return uint64(float64(size)/lineLength*1.1) + 1, nil
+}
+
+func readIDs(r io.Reader, fn func(id int64) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid id %q", lineNumber, line)
+		}
+		if err := fn(id); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading ids: %w", err)
+	}
+	return nil
+}
+
+type spiller struct {
+	dir   string
+	chunk []int64
+	files []string
+}
+
+func (s *spiller) add(id int64) error {
+	s.chunk = append(s.chunk, id)
+	if len(s.chunk) == cap(s.chunk) {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush sorts the chunk, drops the duplicates inside it and writes it to a new
+// spill file.
+func (s *spiller) flush() error {
+	if len(s.chunk) == 0 {
+		return nil
+	}
+	slices.Sort(s.chunk)
+	s.chunk = slices.Compact(s.chunk)
+
+	path := filepath.Join(s.dir, fmt.Sprintf("chunk-%06d", len(s.files)))
+	err := writeSpill(path, func(emit func(id int64) error) error {
+		for _, id := range s.chunk {
+			if err := emit(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.files = append(s.files, path)
+	s.chunk = s.chunk[:0]
+	return nil
+}
+
+// writeSpill creates a spill file and stores every ID that fill emits in it
+// as a little-endian int64 value.
+func writeSpill(path string, fill func(emit func(id int64) error) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating spill file: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	err = fill(func(id int64) error {
+		if err := binary.Write(writer, binary.LittleEndian, id); err != nil {
+			return fmt.Errorf("writing spill file: %w", err)
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+		if err != nil {
+			err = fmt.Errorf("writing spill file: %w", err)
+		}
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing spill file: %w", err)
+	}
+	return nil
+}
+
+type run struct {
+	file   *os.File
+	reader *bufio.Reader
+	head   int64
+}
+
+type runHeap []*run
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].head < h[j].head }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*run)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// next reads the following ID of the run. It returns false at the end of the run.
+func (r *run) next() (bool, error) {
+	err := binary.Read(r.reader, binary.LittleEndian, &r.head)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading spill file: %w", err)
+	}
+	return true, nil
+}
+
+// merge merges the spill files at most fanIn at a time, so that no more than
+// fanIn of them are ever open. While there are more, every group of fanIn
+// files is merged into one longer spill file, and the files it was made of
+// are removed. The last pass writes each ID once to w. It returns the number
+// of unique IDs and of passes.
+func merge(s *spiller, w io.Writer, fanIn int) (int64, int, error) {
+	paths := s.files
+	passes := 1
+	for ; len(paths) > fanIn; passes++ {
+		var merged []string
+		for start := 0; start < len(paths); start += fanIn {
+			group := paths[start:min(start+fanIn, len(paths))]
+			path := filepath.Join(s.dir, fmt.Sprintf("merge-%d-%06d", passes, len(merged)))
+			err := writeSpill(path, func(emit func(id int64) error) error {
+				_, err := mergeRuns(group, emit)
+				return err
+			})
+			if err != nil {
+				return 0, passes, err
+			}
+			for _, done := range group {
+				os.Remove(done)
+			}
+			merged = append(merged, path)
+		}
+		paths = merged
+	}
+
+	writer := bufio.NewWriter(w)
+	unique, err := mergeRuns(paths, func(id int64) error {
+		writer.WriteString(strconv.FormatInt(id, 10))
+		return writer.WriteByte('\n')
+	})
+	if err != nil {
+		return unique, passes, err
+	}
+	if err := writer.Flush(); err != nil {
+		return unique, passes, fmt.Errorf("writing ids: %w", err)
+	}
+	return unique, passes, nil
+}
+
+// mergeRuns does a k-way merge of the sorted spill files and passes each ID
+// to emit once. Every file is closed as soon as all of its IDs are merged.
+func mergeRuns(paths []string, emit func(id int64) error) (int64, error) {
+	h := &runHeap{}
+	defer func() {
+		for _, r := range *h {
+			r.file.Close()
+		}
+	}()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, fmt.Errorf("opening spill file: %w", err)
+		}
+		r := &run{file: file, reader: bufio.NewReader(file)}
+		ok, err := r.next()
+		if err != nil || !ok {
+			file.Close()
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+		heap.Push(h, r)
+	}
+
+	var unique int64
+	var last int64
+	for h.Len() > 0 {
+		r := (*h)[0]
+		if unique == 0 || r.head != last {
+			last = r.head
+			unique++
+			if err := emit(last); err != nil {
+				return unique, err
+			}
+		}
+
+		ok, err := r.next()
+		if err != nil {
+			return unique, err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+			r.file.Close()
+		}
+	}
+	return unique, nil
+}
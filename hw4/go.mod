@@ -0,0 +1,3 @@
+module GoLangProjector/hw4
+
+go 1.22.3
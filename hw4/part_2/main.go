@@ -0,0 +1,95 @@
+package main
+
+import (
+	"GoLangProjector/hw4/dedup"
+	"GoLangProjector/hw4/extsort"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	idsPath := flag.String("ids", "", "file with one user ID per line to deduplicate and sort on disk")
+	outPath := flag.String("out", "", "file for the unique sorted IDs (default stdout)")
+	chunkSize := flag.Int("chunk", 1<<20, "IDs sorted in memory before spilling to the temp directory")
+	tempDir := flag.String("tmp", "", "directory for spill files (default system temp directory)")
+	bloom := flag.Bool("bloom", false, "run a Bloom-filter pre-pass to drop duplicates before spilling")
+	fanIn := flag.Int("fan-in", 64, "spill files merged, and kept open, at a time")
+	expected := flag.Uint64("expected", 0, "expected number of IDs, sizes the Bloom filter (0 estimates it from the file size)")
+	flag.Parse()
+
+	if *idsPath != "" {
+		err := uniqueUserIDsFromFile(*idsPath, *outPath, extsort.Options{
+			ChunkSize:     *chunkSize,
+			TempDir:       *tempDir,
+			Bloom:         *bloom,
+			ExpectedItems: *expected,
+			MergeFanIn:    *fanIn,
+		})
+		if err != nil {
+			fmt.Println("Error is occurred:", err)
+		}
+		return
+	}
+
+	users := []User{
+		{Id: 10},
+		{Id: 1},
+		{Id: 3},
+		{Id: 7},
+		{Id: 1},
+		{Id: 5},
+		{Id: 3},
+	}
+	uniqueUsers, duplicates := uniqueUser(users)
+	for _, user := range duplicates {
+		fmt.Printf("User with Id %d already exists\n", user.Id)
+	}
+	fmt.Println("Unique and Sorted Users:", uniqueUsers)
+}
+
+type User struct {
+	Id int
+}
+
+func uniqueUser(users []User) ([]User, []User) {
+	result := dedup.Unique(users, func(u User) int { return u.Id })
+	userSort(result.Unique)
+
+	return result.Unique, result.Duplicates
+}
+
+func userSort(users []User) []User {
+	return dedup.SortBy(users, dedup.Asc(func(u User) int { return u.Id }))
+}
+
+func uniqueUserIDsFromFile(idsPath string, outPath string, opts extsort.Options) error {
+	in, err := os.Open(idsPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out := os.Stdout
+	if outPath != "" {
+		out, err = os.Create(outPath)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	stats, err := extsort.UniqueSorted(in, out, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Read %d IDs, %d unique, %d duplicates, %d spill files, %d merge passes",
+		stats.Read, stats.Unique, stats.Duplicates, stats.SpillFiles, stats.MergePasses)
+	if stats.BloomSkipped {
+		fmt.Fprintf(os.Stderr, ", Bloom pre-pass skipped (filter for %d IDs too small or too many candidates)", stats.ExpectedItems)
+	} else if opts.Bloom {
+		fmt.Fprintf(os.Stderr, ", %d Bloom candidates, filter for %d IDs", stats.Candidates, stats.ExpectedItems)
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
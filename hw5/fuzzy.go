@@ -0,0 +1,105 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+type Suggestion struct {
+	Word     string `json:"word"`
+	Distance int    `json:"distance"`
+	Lines    int    `json:"lines"`
+}
+
+// Suggest returns the indexed words within maxDistance edits of word,
+// closest first and, for the same distance, the most common first.
+func (idx *Index) Suggest(word string, maxDistance int) []Suggestion {
+	idx.m.RLock()
+	defer idx.m.RUnlock()
+
+	return idx.suggest(word, maxDistance)
+}
+
+// Correct replaces every query word that is not in the index with its best
+// suggestion. Words without a suggestion are kept as they are.
+func (idx *Index) Correct(query string, maxDistance int) string {
+	idx.m.RLock()
+	defer idx.m.RUnlock()
+
+	words := splitWords(query)
+	for i, word := range words {
+		if len(idx.postings[word]) > 0 {
+			continue
+		}
+		suggestions := idx.suggest(word, maxDistance)
+		if len(suggestions) > 0 {
+			words[i] = suggestions[0].Word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func (idx *Index) suggest(word string, maxDistance int) []Suggestion {
+	word = strings.ToLower(word)
+	var suggestions []Suggestion
+	for candidate, postings := range idx.postings {
+		distance := editDistance(word, candidate, maxDistance)
+		if distance > maxDistance {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			Word:     candidate,
+			Distance: distance,
+			Lines:    len(postings),
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		if suggestions[i].Lines != suggestions[j].Lines {
+			return suggestions[i].Lines > suggestions[j].Lines
+		}
+		return suggestions[i].Word < suggestions[j].Word
+	})
+	return suggestions
+}
+
+// editDistance is the Levenshtein distance between a and b. It stops early and
+// returns maxDistance+1 once the distance is known to be larger than maxDistance.
+func editDistance(a, b string, maxDistance int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > maxDistance {
+		return maxDistance + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,3 @@
+module GoLangProjector/hw5
+
+go 1.22.3
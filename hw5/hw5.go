@@ -2,29 +2,74 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
 )
 
 func main() {
-	file, err := openFile()
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		runStats(os.Args[2:])
+		return
+	}
+
+	top := flag.Int("top", 0, "show only the N most relevant lines (0 shows all)")
+	rank := flag.String("rank", rankBM25, "ranking function: bm25 or tfidf")
+	maxDistance := flag.Int("distance", 2, "maximum edit distance for \"did you mean\" suggestions")
+	addr := flag.String("serve", "", "serve the search API on this address instead of asking for a query, e.g. :8080")
+	flag.Parse()
+	if *rank != rankBM25 && *rank != rankTFIDF {
+		fmt.Println("Unknown ranking function:", *rank)
+		return
+	}
+
+	if *addr != "" {
+		filePaths := flag.Args()
+		if len(filePaths) == 0 {
+			filePaths = []string{"testHw5.txt"}
+		}
+		index, err := indexFiles(filePaths)
+		if err != nil {
+			fmt.Println("Error indexing files:", err)
+			return
+		}
+		if err := serve(*addr, index, *rank, *maxDistance); err != nil {
+			fmt.Println("Error is occurred: ", err.Error())
+		}
+		return
+	}
+
+	input := bufio.NewScanner(os.Stdin)
+	file, filePath, err := openFile(input)
 	if err != nil {
 		fmt.Println("Error open file:", err)
 		return
 	}
 	textLines := readData(file)
+	file.Close()
+
+	index := NewIndex()
+	index.Add(filePath, textLines)
 
-	mapByWords := initTextByWords(textLines)
-	input := inputWord(err)
-	printAllLinesByWorld(input, mapByWords)
+	query := inputWord(input)
+	hits := index.Search(query, *rank, *top)
+	if len(hits) == 0 {
+		printAllLinesByWorld(query, nil)
+		corrected := index.Correct(query, *maxDistance)
+		if corrected == "" || corrected == strings.Join(splitWords(query), " ") {
+			return
+		}
+		fmt.Printf("Did you mean [%s]?\n", corrected)
+		query, hits = corrected, index.Search(corrected, *rank, *top)
+	}
+	printAllLinesByWorld(query, hits)
 }
 
-func openFile() (*os.File, error) {
-	scannerFile := bufio.NewScanner(os.Stdin)
+func openFile(input *bufio.Scanner) (*os.File, string, error) {
 	fmt.Print("Enter the full path to the target file: ")
-	scannerFile.Scan()
-	filePath := scannerFile.Text()
+	input.Scan()
+	filePath := input.Text()
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -32,11 +77,10 @@ func openFile() (*os.File, error) {
 		fmt.Println("Opened default file")
 		filePath = "testHw5.txt"
 		file, err = os.Open(filePath)
-		return file, err
+		return file, filePath, err
 	}
-	defer file.Close()
 	fmt.Println("File opened successfully:", filePath)
-	return file, nil
+	return file, filePath, nil
 }
 
 func readData(file *os.File) []string {
@@ -54,12 +98,42 @@ func readData(file *os.File) []string {
 	return textLines
 }
 
-func inputWord(err error) string {
-	fmt.Print("Enter word which you want find:")
-	var input string
-	_, err = fmt.Scanln(&input)
-	fmt.Println("You entered:", input)
-	return input
+func indexFiles(filePaths []string) (*Index, error) {
+	index := NewIndex()
+	for _, filePath := range filePaths {
+		textLines, err := loadLines(filePath)
+		if err != nil {
+			return nil, err
+		}
+		index.Add(filePath, textLines)
+	}
+	return index, nil
+}
+
+func loadLines(filePath string) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var textLines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		textLines = append(textLines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filePath, err)
+	}
+	return textLines, nil
+}
+
+func inputWord(input *bufio.Scanner) string {
+	fmt.Print("Enter words which you want find:")
+	input.Scan()
+	query := input.Text()
+	fmt.Println("You entered:", query)
+	return query
 }
 
 func initTextByWords(textLines []string) map[string][]string {
@@ -67,8 +141,7 @@ func initTextByWords(textLines []string) map[string][]string {
 	initMap := make(map[string][]string)
 
 	for _, textLine := range textLines {
-		splitWords := strings.Fields(textLine)
-		for _, splitWord := range splitWords {
+		for _, splitWord := range uniqueWords(splitWords(textLine)) {
 
 			initMap[splitWord] = append(initMap[splitWord], textLine)
 		}
@@ -76,14 +149,13 @@ func initTextByWords(textLines []string) map[string][]string {
 	return initMap
 }
 
-func printAllLinesByWorld(word string, textLines map[string][]string) {
-	vals := textLines[word]
-	if len(vals) == 0 {
-		fmt.Printf("Word [%s] doesn't exist \n", word)
+func printAllLinesByWorld(query string, hits []Hit) {
+	if len(hits) == 0 {
+		fmt.Printf("Words [%s] don't exist \n", query)
 		return
 	}
-	fmt.Printf("Word [%s] exists in the lines:\n", word)
-	for _, val := range vals {
-		fmt.Println(val)
+	fmt.Printf("Words [%s] exist in the lines:\n", query)
+	for _, hit := range hits {
+		fmt.Printf("%6.3f  %s:%d  %s\n", hit.Score, hit.Doc.File, hit.Doc.Line, hit.Doc.Text)
 	}
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	rankBM25  = "bm25"
+	rankTFIDF = "tfidf"
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+type Document struct {
+	ID     int
+	File   string
+	Line   int
+	Text   string
+	Length int
+}
+
+type posting struct {
+	doc  int
+	freq int
+}
+
+type Index struct {
+	m           sync.RWMutex
+	docs        []Document
+	postings    map[string][]posting
+	totalLength int
+}
+
+type Hit struct {
+	Doc   Document
+	Score float64
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string][]posting),
+	}
+}
+
+// Add indexes every line of the file as a separate document.
+func (idx *Index) Add(file string, textLines []string) {
+	idx.m.Lock()
+	defer idx.m.Unlock()
+
+	for i, textLine := range textLines {
+		words := splitWords(textLine)
+		doc := Document{
+			ID:     len(idx.docs),
+			File:   file,
+			Line:   i + 1,
+			Text:   textLine,
+			Length: len(words),
+		}
+		idx.docs = append(idx.docs, doc)
+		idx.totalLength += doc.Length
+
+		freqs := make(map[string]int)
+		for _, word := range words {
+			freqs[word]++
+		}
+		for word, freq := range freqs {
+			idx.postings[word] = append(idx.postings[word], posting{doc: doc.ID, freq: freq})
+		}
+	}
+}
+
+// Search scores every document containing at least one query word and returns
+// them ordered by relevance. top <= 0 returns all hits.
+func (idx *Index) Search(query string, rank string, top int) []Hit {
+	idx.m.RLock()
+	defer idx.m.RUnlock()
+
+	if len(idx.docs) == 0 {
+		return nil
+	}
+	scores := make(map[int]float64)
+	avgLength := float64(idx.totalLength) / float64(len(idx.docs))
+
+	for _, word := range uniqueWords(splitWords(query)) {
+		postings := idx.postings[word]
+		if len(postings) == 0 {
+			continue
+		}
+		for _, p := range postings {
+			length := float64(idx.docs[p.doc].Length)
+			if rank == rankTFIDF {
+				scores[p.doc] += idx.tfidf(p.freq, length, len(postings))
+			} else {
+				scores[p.doc] += idx.bm25(p.freq, length, avgLength, len(postings))
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for docID, score := range scores {
+		hits = append(hits, Hit{Doc: idx.docs[docID], Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc.ID < hits[j].Doc.ID
+	})
+	if top > 0 && len(hits) > top {
+		hits = hits[:top]
+	}
+	return hits
+}
+
+func (idx *Index) tfidf(freq int, length float64, docFreq int) float64 {
+	tf := float64(freq) / length
+	idf := math.Log(float64(len(idx.docs))/float64(docFreq)) + 1
+	return tf * idf
+}
+
+func (idx *Index) bm25(freq int, length float64, avgLength float64, docFreq int) float64 {
+	n := float64(len(idx.docs))
+	idf := math.Log(1 + (n-float64(docFreq)+0.5)/(float64(docFreq)+0.5))
+	tf := float64(freq)
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*length/avgLength))
+}
+
+// splitWords lowercases the line and strips punctuation around each word,
+// so "Sky," and "sky" are the same term.
+func splitWords(textLine string) []string {
+	var words []string
+	for _, field := range strings.Fields(textLine) {
+		word := strings.TrimFunc(strings.ToLower(field), func(r rune) bool {
+			return !isWordRune(r)
+		})
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func uniqueWords(words []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			unique = append(unique, word)
+		}
+	}
+	return unique
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
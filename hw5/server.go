@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type SearchHit struct {
+	File    string  `json:"file"`
+	Line    int     `json:"line"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+type NewDocument struct {
+	File string `json:"file"`
+	Text string `json:"text"`
+}
+
+type SearchResource struct {
+	index       *Index
+	rank        string
+	maxDistance int
+}
+
+func (sR *SearchResource) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		http.Error(w, "Missing q param", http.StatusBadRequest)
+		return
+	}
+	top := 0
+	if topVal := r.URL.Query().Get("top"); topVal != "" {
+		var err error
+		top, err = strconv.Atoi(topVal)
+		if err != nil {
+			http.Error(w, "Invalid top param", http.StatusBadRequest)
+			return
+		}
+	}
+
+	queryWords := make(map[string]bool)
+	for _, word := range splitWords(query) {
+		queryWords[word] = true
+	}
+	hits := sR.index.Search(query, sR.rank, top)
+	searchHits := make([]SearchHit, len(hits))
+	for i, hit := range hits {
+		searchHits[i] = SearchHit{
+			File:    hit.Doc.File,
+			Line:    hit.Doc.Line,
+			Score:   hit.Score,
+			Snippet: highlight(hit.Doc.Text, queryWords),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(searchHits)
+	if err != nil {
+		fmt.Printf("Failed to encode: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (sR *SearchResource) Suggest(w http.ResponseWriter, r *http.Request) {
+	word := strings.TrimSpace(r.URL.Query().Get("q"))
+	if word == "" {
+		http.Error(w, "Missing q param", http.StatusBadRequest)
+		return
+	}
+	maxDistance := sR.maxDistance
+	if distanceVal := r.URL.Query().Get("distance"); distanceVal != "" {
+		var err error
+		maxDistance, err = strconv.Atoi(distanceVal)
+		if err != nil || maxDistance < 0 {
+			http.Error(w, "Invalid distance param", http.StatusBadRequest)
+			return
+		}
+	}
+
+	suggestions := sR.index.Suggest(word, maxDistance)
+	if suggestions == nil {
+		suggestions = []Suggestion{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(suggestions)
+	if err != nil {
+		fmt.Printf("Failed to encode: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (sR *SearchResource) AddDocument(w http.ResponseWriter, r *http.Request) {
+	var doc NewDocument
+	err := json.NewDecoder(r.Body).Decode(&doc)
+	if err != nil {
+		http.Error(w, "Failed to decode document, error:"+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if doc.File == "" || doc.Text == "" {
+		http.Error(w, "Document needs file and text", http.StatusBadRequest)
+		return
+	}
+
+	sR.index.Add(doc.File, strings.Split(doc.Text, "\n"))
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// highlight HTML-escapes the line and wraps every query word in <mark> tags,
+// leaving the punctuation around the word outside the tags.
+func highlight(textLine string, queryWords map[string]bool) string {
+	var b strings.Builder
+	for len(textLine) > 0 {
+		end := strings.IndexFunc(textLine, unicode.IsSpace)
+		if end == 0 {
+			end = strings.IndexFunc(textLine, func(r rune) bool { return !unicode.IsSpace(r) })
+			if end < 0 {
+				end = len(textLine)
+			}
+			b.WriteString(textLine[:end])
+			textLine = textLine[end:]
+			continue
+		}
+		if end < 0 {
+			end = len(textLine)
+		}
+		field := textLine[:end]
+		textLine = textLine[end:]
+
+		words := splitWords(field)
+		if len(words) == 0 || !queryWords[words[0]] {
+			b.WriteString(html.EscapeString(field))
+			continue
+		}
+		start := strings.IndexFunc(field, isWordRune)
+		stop := strings.LastIndexFunc(field, isWordRune)
+		_, size := utf8.DecodeRuneInString(field[stop:])
+		b.WriteString(html.EscapeString(field[:start]))
+		b.WriteString("<mark>" + html.EscapeString(field[start:stop+size]) + "</mark>")
+		b.WriteString(html.EscapeString(field[stop+size:]))
+	}
+	return b.String()
+}
+
+func serve(addr string, index *Index, rank string, maxDistance int) error {
+	mux := http.NewServeMux()
+
+	searchResource := SearchResource{index: index, rank: rank, maxDistance: maxDistance}
+	mux.HandleFunc("GET /search", searchResource.Search)
+	mux.HandleFunc("GET /suggest", searchResource.Suggest)
+	mux.HandleFunc("POST /documents", searchResource.AddDocument)
+
+	fmt.Println("Listening on", addr)
+	return http.ListenAndServe(addr, mux)
+}
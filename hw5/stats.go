@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+	Lines int    `json:"lines"`
+}
+
+type NgramCount struct {
+	Ngram string `json:"ngram"`
+	Count int    `json:"count"`
+}
+
+type Stats struct {
+	Lines             int          `json:"lines"`
+	Words             int          `json:"words"`
+	VocabularySize    int          `json:"vocabularySize"`
+	AverageLineWords  float64      `json:"averageLineWords"`
+	AverageLineLength float64      `json:"averageLineLength"`
+	TopWords          []WordCount  `json:"topWords"`
+	Bigrams           []NgramCount `json:"bigrams"`
+	Trigrams          []NgramCount `json:"trigrams"`
+}
+
+func runStats(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text, csv or json")
+	top := flags.Int("top", 10, "number of words and n-grams to show (0 shows all)")
+	flags.Parse(args)
+
+	filePaths := flags.Args()
+	if len(filePaths) == 0 {
+		filePaths = []string{"testHw5.txt"}
+	}
+	var textLines []string
+	for _, filePath := range filePaths {
+		lines, err := loadLines(filePath)
+		if err != nil {
+			fmt.Println("Error reading file:", err)
+			return
+		}
+		textLines = append(textLines, lines...)
+	}
+
+	stats := calculateStats(textLines, initTextByWords(textLines), *top)
+
+	var err error
+	switch *format {
+	case "text":
+		err = writeStatsText(os.Stdout, stats)
+	case "csv":
+		err = writeStatsCSV(os.Stdout, stats)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(stats)
+	default:
+		fmt.Println("Unknown format:", *format)
+		return
+	}
+	if err != nil {
+		fmt.Println("Error writing stats:", err)
+	}
+}
+
+// calculateStats counts words and n-grams per line. N-grams never cross line
+// boundaries. mapByWords is the word to lines map built by initTextByWords.
+func calculateStats(textLines []string, mapByWords map[string][]string, top int) Stats {
+	stats := Stats{
+		Lines:          len(textLines),
+		VocabularySize: len(mapByWords),
+	}
+	wordCounts := make(map[string]int)
+	bigrams := make(map[string]int)
+	trigrams := make(map[string]int)
+	totalChars := 0
+
+	for _, textLine := range textLines {
+		totalChars += utf8.RuneCountInString(textLine)
+		words := splitWords(textLine)
+		stats.Words += len(words)
+		for i, word := range words {
+			wordCounts[word]++
+			if i >= 1 {
+				bigrams[strings.Join(words[i-1:i+1], " ")]++
+			}
+			if i >= 2 {
+				trigrams[strings.Join(words[i-2:i+1], " ")]++
+			}
+		}
+	}
+	if stats.Lines > 0 {
+		stats.AverageLineWords = float64(stats.Words) / float64(stats.Lines)
+		stats.AverageLineLength = float64(totalChars) / float64(stats.Lines)
+	}
+
+	for word, count := range wordCounts {
+		stats.TopWords = append(stats.TopWords, WordCount{
+			Word:  word,
+			Count: count,
+			Lines: len(mapByWords[word]),
+		})
+	}
+	sort.Slice(stats.TopWords, func(i, j int) bool {
+		if stats.TopWords[i].Count != stats.TopWords[j].Count {
+			return stats.TopWords[i].Count > stats.TopWords[j].Count
+		}
+		return stats.TopWords[i].Word < stats.TopWords[j].Word
+	})
+	if top > 0 && len(stats.TopWords) > top {
+		stats.TopWords = stats.TopWords[:top]
+	}
+	stats.Bigrams = topNgrams(bigrams, top)
+	stats.Trigrams = topNgrams(trigrams, top)
+	return stats
+}
+
+func topNgrams(counts map[string]int, top int) []NgramCount {
+	ngrams := make([]NgramCount, 0, len(counts))
+	for ngram, count := range counts {
+		ngrams = append(ngrams, NgramCount{Ngram: ngram, Count: count})
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		if ngrams[i].Count != ngrams[j].Count {
+			return ngrams[i].Count > ngrams[j].Count
+		}
+		return ngrams[i].Ngram < ngrams[j].Ngram
+	})
+	if top > 0 && len(ngrams) > top {
+		ngrams = ngrams[:top]
+	}
+	return ngrams
+}
+
+func writeStatsText(w io.Writer, stats Stats) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Lines: %d\n", stats.Lines)
+	fmt.Fprintf(&b, "Words: %d\n", stats.Words)
+	fmt.Fprintf(&b, "Vocabulary size: %d\n", stats.VocabularySize)
+	fmt.Fprintf(&b, "Average line length: %.2f words, %.2f characters\n", stats.AverageLineWords, stats.AverageLineLength)
+	fmt.Fprintf(&b, "\nTop words:\n")
+	for _, wc := range stats.TopWords {
+		fmt.Fprintf(&b, "%6d  %-20s in %d lines\n", wc.Count, wc.Word, wc.Lines)
+	}
+	fmt.Fprintf(&b, "\nTop bigrams:\n")
+	for _, nc := range stats.Bigrams {
+		fmt.Fprintf(&b, "%6d  %s\n", nc.Count, nc.Ngram)
+	}
+	fmt.Fprintf(&b, "\nTop trigrams:\n")
+	for _, nc := range stats.Trigrams {
+		fmt.Fprintf(&b, "%6d  %s\n", nc.Count, nc.Ngram)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeStatsCSV writes one row per value as kind,item,count,lines.
+func writeStatsCSV(w io.Writer, stats Stats) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"kind", "item", "count", "lines"},
+		{"summary", "lines", strconv.Itoa(stats.Lines), ""},
+		{"summary", "words", strconv.Itoa(stats.Words), ""},
+		{"summary", "vocabularySize", strconv.Itoa(stats.VocabularySize), ""},
+		{"summary", "averageLineWords", strconv.FormatFloat(stats.AverageLineWords, 'f', 2, 64), ""},
+		{"summary", "averageLineLength", strconv.FormatFloat(stats.AverageLineLength, 'f', 2, 64), ""},
+	}
+	for _, wc := range stats.TopWords {
+		rows = append(rows, []string{"word", wc.Word, strconv.Itoa(wc.Count), strconv.Itoa(wc.Lines)})
+	}
+	for _, nc := range stats.Bigrams {
+		rows = append(rows, []string{"bigram", nc.Ngram, strconv.Itoa(nc.Count), ""})
+	}
+	for _, nc := range stats.Trigrams {
+		rows = append(rows, []string{"trigram", nc.Ngram, strconv.Itoa(nc.Count), ""})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"GoLangProjector/hw6/fare"
+	"GoLangProjector/hw6/route"
+	"GoLangProjector/hw6/ticket"
+)
+
+type Vehicle struct {
+	ID         int    `json:"id"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Capacity   int    `json:"capacity"`
+	Passengers int    `json:"passengers"`
+	FreeSeats  int    `json:"freeSeats"`
+}
+
+type Leg struct {
+	Position  int    `json:"position"`
+	VehicleID int    `json:"vehicleId"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Departure string `json:"departure,omitempty"`
+	Arrival   string `json:"arrival,omitempty"`
+}
+
+type Route struct {
+	Name string `json:"name"`
+	Legs []Leg  `json:"legs"`
+}
+
+type AddVehicle struct {
+	VehicleID int `json:"vehicleId"`
+}
+
+type TripRequest struct {
+	Route    string        `json:"route"`
+	Name     string        `json:"name"`
+	Category fare.Category `json:"category"`
+	Class    fare.Class    `json:"class"`
+}
+
+type Trip struct {
+	TicketID int            `json:"ticketId"`
+	Route    string         `json:"route"`
+	Vehicles []string       `json:"vehicles"`
+	Price    float64        `json:"price"`
+	Status   ticket.Status  `json:"status"`
+	Fare     fare.Breakdown `json:"fare"`
+}
+
+type Refund struct {
+	TicketID int     `json:"ticketId"`
+	Amount   float64 `json:"amount"`
+}
+
+func toVehicle(e *vehicleEntry) Vehicle {
+	return Vehicle{
+		ID:         e.id,
+		Type:       e.vehicle.GetType(),
+		Name:       e.vehicle.GetName(),
+		Capacity:   e.capacity,
+		Passengers: e.vehicle.Passengers(),
+		FreeSeats:  e.vehicle.FreeSeats(),
+	}
+}
+
+func toRoute(r *route.Route, vehicleIDs []int) Route {
+	dto := Route{Name: r.Name, Legs: make([]Leg, len(r.Legs))}
+	for i, leg := range r.Legs {
+		dto.Legs[i] = Leg{
+			Position: i + 1,
+			Type:     leg.Vehicle.GetType(),
+			Name:     leg.Vehicle.GetName(),
+		}
+		if i < len(vehicleIDs) {
+			dto.Legs[i].VehicleID = vehicleIDs[i]
+		}
+		if leg.From != nil && leg.To != nil {
+			dto.Legs[i].From = leg.From.Name
+			dto.Legs[i].To = leg.To.Name
+			dto.Legs[i].Departure = route.FormatClock(leg.Departure)
+			dto.Legs[i].Arrival = route.FormatClock(leg.Arrival)
+		}
+	}
+	return dto
+}
+
+func toTrip(t ticket.Ticket) Trip {
+	return Trip{
+		TicketID: t.ID,
+		Route:    t.Route,
+		Vehicles: t.Vehicles,
+		Price:    t.Price,
+		Status:   t.Status,
+		Fare:     t.Fare,
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"GoLangProjector/hw6/publicTransport"
+	"GoLangProjector/hw6/ticket"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type Resource struct {
+	s *Storage
+}
+
+func NewResource(s *Storage) *Resource {
+	return &Resource{s: s}
+}
+
+// Register adds every route of the API to mux.
+func (res *Resource) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /vehicles", res.GetAllVehicles)
+	mux.HandleFunc("POST /vehicles", res.CreateVehicle)
+	mux.HandleFunc("GET /vehicles/{id}", res.GetVehicle)
+	mux.HandleFunc("PUT /vehicles/{id}", res.UpdateVehicle)
+	mux.HandleFunc("DELETE /vehicles/{id}", res.DeleteVehicle)
+
+	mux.HandleFunc("GET /routes", res.GetAllRoutes)
+	mux.HandleFunc("POST /routes", res.CreateRoute)
+	mux.HandleFunc("GET /routes/{name}", res.GetRoute)
+	mux.HandleFunc("DELETE /routes/{name}", res.DeleteRoute)
+	mux.HandleFunc("POST /routes/{name}/vehicles", res.AddVehicleToRoute)
+
+	mux.HandleFunc("GET /passengers/{id}/trips", res.GetTrips)
+	mux.HandleFunc("POST /passengers/{id}/trips", res.BookTrip)
+	mux.HandleFunc("DELETE /passengers/{id}/trips/{ticketId}", res.CancelTrip)
+}
+
+func (res *Resource) GetAllVehicles(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, res.s.GetAllVehicles())
+}
+
+func (res *Resource) GetVehicle(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	vehicle, err := res.s.GetVehicle(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, vehicle)
+}
+
+func (res *Resource) CreateVehicle(w http.ResponseWriter, r *http.Request) {
+	var vehicle Vehicle
+	if !decode(w, r, &vehicle) {
+		return
+	}
+	vehicle, err := res.s.CreateVehicle(vehicle)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, vehicle)
+}
+
+func (res *Resource) UpdateVehicle(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	var vehicle Vehicle
+	if !decode(w, r, &vehicle) {
+		return
+	}
+	vehicle, err := res.s.UpdateVehicle(id, vehicle)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, vehicle)
+}
+
+func (res *Resource) DeleteVehicle(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	if err := res.s.DeleteVehicle(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (res *Resource) GetAllRoutes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, res.s.GetAllRoutes())
+}
+
+func (res *Resource) GetRoute(w http.ResponseWriter, r *http.Request) {
+	rt, err := res.s.GetRoute(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rt)
+}
+
+func (res *Resource) CreateRoute(w http.ResponseWriter, r *http.Request) {
+	var rt Route
+	if !decode(w, r, &rt) {
+		return
+	}
+	rt, err := res.s.CreateRoute(rt.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rt)
+}
+
+func (res *Resource) DeleteRoute(w http.ResponseWriter, r *http.Request) {
+	if err := res.s.DeleteRoute(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (res *Resource) AddVehicleToRoute(w http.ResponseWriter, r *http.Request) {
+	var add AddVehicle
+	if !decode(w, r, &add) {
+		return
+	}
+	rt, err := res.s.AddVehicleToRoute(r.PathValue("name"), add.VehicleID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rt)
+}
+
+func (res *Resource) GetTrips(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	tickets, err := res.s.GetTrips(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	trips := make([]Trip, len(tickets))
+	for i, t := range tickets {
+		trips[i] = toTrip(t)
+	}
+	writeJSON(w, http.StatusOK, trips)
+}
+
+func (res *Resource) BookTrip(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req TripRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	t, err := res.s.BookTrip(id, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toTrip(t))
+}
+
+func (res *Resource) CancelTrip(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	ticketID, ok := pathID(w, r, "ticketId")
+	if !ok {
+		return
+	}
+	refund, err := res.s.CancelTrip(id, ticketID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Refund{TicketID: ticketID, Amount: refund})
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		http.Error(w, "Invalid "+name+" param", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, "Failed to decode request, error:"+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("Failed to encode: %v\n", err)
+	}
+}
+
+// writeError maps storage and domain errors to HTTP status codes.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrSave):
+		status = http.StatusInternalServerError
+	case errors.Is(err, ErrNotFound), errors.Is(err, ticket.ErrTicketNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrExists), errors.Is(err, ErrInUse), errors.Is(err, publicTransport.ErrFull),
+		errors.Is(err, ticket.ErrTicketNotBooked):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,430 @@
+package api
+
+import (
+	"GoLangProjector/hw6/events"
+	"GoLangProjector/hw6/passengers"
+	"GoLangProjector/hw6/publicTransport"
+	"GoLangProjector/hw6/route"
+	"GoLangProjector/hw6/ticket"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrExists   = errors.New("already exists")
+	ErrSave     = errors.New("failed to save state")
+	ErrInUse    = errors.New("has booked tickets")
+)
+
+type vehicleRecord struct {
+	ID         int    `json:"id"`
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Capacity   int    `json:"capacity"`
+	Passengers int    `json:"passengers"`
+}
+
+type routeRecord struct {
+	Name       string `json:"name"`
+	VehicleIDs []int  `json:"vehicleIds"`
+}
+
+type snapshot struct {
+	LastVehicleID int             `json:"lastVehicleId"`
+	Vehicles      []vehicleRecord `json:"vehicles"`
+	Routes        []routeRecord   `json:"routes"`
+}
+
+type vehicleEntry struct {
+	id       int
+	capacity int
+	vehicle  publicTransport.PublicTransport
+}
+
+// Storage keeps vehicles and routes in memory and writes them to a JSON file
+// after every change, so their state survives a restart. Tickets are kept in
+// memory only, so the saved passenger counts leave out the seats they hold.
+type Storage struct {
+	m             sync.Mutex
+	path          string
+	lastVehicleID int
+	vehicles      map[int]*vehicleEntry
+	routes        map[string]*route.Route
+	routeVehicles map[string][]int
+	passengers    map[int]*passengers.Passenger
+	office        *ticket.Office
+	sink          events.Sink
+}
+
+// NewStorage loads the state saved at path. Every vehicle and route sends its
+// events to sink, which may be nil.
+func NewStorage(path string, office *ticket.Office, sink events.Sink) (*Storage, error) {
+	s := &Storage{
+		path:          path,
+		sink:          sink,
+		vehicles:      make(map[int]*vehicleEntry),
+		routes:        make(map[string]*route.Route),
+		routeVehicles: make(map[string][]int),
+		passengers:    make(map[int]*passengers.Passenger),
+		office:        office,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Storage) GetAllVehicles() []Vehicle {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var vehicles = make([]Vehicle, 0, len(s.vehicles))
+	for _, e := range s.vehicles {
+		vehicles = append(vehicles, toVehicle(e))
+	}
+	sort.Slice(vehicles, func(i, j int) bool {
+		return vehicles[i].ID < vehicles[j].ID
+	})
+	return vehicles
+}
+
+func (s *Storage) GetVehicle(id int) (Vehicle, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	e, ok := s.vehicles[id]
+	if !ok {
+		return Vehicle{}, fmt.Errorf("vehicle %d %w", id, ErrNotFound)
+	}
+	return toVehicle(e), nil
+}
+
+// CreateVehicle keeps the new vehicle only once it is saved, so a failed
+// save can be retried without creating it twice.
+func (s *Storage) CreateVehicle(v Vehicle) (Vehicle, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	e, err := s.newVehicleEntry(s.lastVehicleID+1, v)
+	if err != nil {
+		return Vehicle{}, err
+	}
+	s.lastVehicleID++
+	s.vehicles[e.id] = e
+	if err := s.save(); err != nil {
+		delete(s.vehicles, e.id)
+		s.lastVehicleID--
+		return Vehicle{}, err
+	}
+	return toVehicle(e), nil
+}
+
+// UpdateVehicle replaces the vehicle everywhere it is used, routes included.
+// Booked tickets hold seats on the old vehicle, so it can't be replaced
+// until they are cancelled.
+func (s *Storage) UpdateVehicle(id int, v Vehicle) (Vehicle, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	old, ok := s.vehicles[id]
+	if !ok {
+		return Vehicle{}, fmt.Errorf("vehicle %d %w", id, ErrNotFound)
+	}
+	if seats := s.office.Reserved(old.vehicle); seats > 0 {
+		return Vehicle{}, fmt.Errorf("vehicle %d %w for %d seats", id, ErrInUse, seats)
+	}
+	e, err := s.newVehicleEntry(id, v)
+	if err != nil {
+		return Vehicle{}, err
+	}
+	s.vehicles[id] = e
+	for _, r := range s.routes {
+		for i := range r.Legs {
+			if r.Legs[i].Vehicle == old.vehicle {
+				r.Legs[i].Vehicle = e.vehicle
+				r.Vehicles[i] = e.vehicle
+			}
+		}
+	}
+	return toVehicle(e), s.save()
+}
+
+// DeleteVehicle removes the vehicle from every route. Like UpdateVehicle, it
+// refuses while booked tickets hold seats on it.
+func (s *Storage) DeleteVehicle(id int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	e, ok := s.vehicles[id]
+	if !ok {
+		return fmt.Errorf("vehicle %d %w", id, ErrNotFound)
+	}
+	if seats := s.office.Reserved(e.vehicle); seats > 0 {
+		return fmt.Errorf("vehicle %d %w for %d seats", id, ErrInUse, seats)
+	}
+	delete(s.vehicles, id)
+	for name, ids := range s.routeVehicles {
+		if slices.Contains(ids, id) {
+			s.routeVehicles[name] = slices.DeleteFunc(ids, func(v int) bool { return v == id })
+			s.routes[name] = s.buildRoute(name)
+		}
+	}
+	return s.save()
+}
+
+func (s *Storage) GetAllRoutes() []Route {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var routes = make([]Route, 0, len(s.routes))
+	for name, r := range s.routes {
+		routes = append(routes, toRoute(r, s.routeVehicles[name]))
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Name < routes[j].Name
+	})
+	return routes
+}
+
+func (s *Storage) GetRoute(name string) (Route, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	r, ok := s.routes[name]
+	if !ok {
+		return Route{}, fmt.Errorf("route %s %w", name, ErrNotFound)
+	}
+	return toRoute(r, s.routeVehicles[name]), nil
+}
+
+func (s *Storage) CreateRoute(name string) (Route, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if name == "" {
+		return Route{}, errors.New("route needs a name")
+	}
+	if _, ok := s.routes[name]; ok {
+		return Route{}, fmt.Errorf("route %s %w", name, ErrExists)
+	}
+	s.routes[name] = s.buildRoute(name)
+	s.routeVehicles[name] = nil
+	if err := s.save(); err != nil {
+		delete(s.routes, name)
+		delete(s.routeVehicles, name)
+		return Route{}, err
+	}
+	return toRoute(s.routes[name], nil), nil
+}
+
+func (s *Storage) DeleteRoute(name string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.routes[name]; !ok {
+		return fmt.Errorf("route %s %w", name, ErrNotFound)
+	}
+	delete(s.routes, name)
+	delete(s.routeVehicles, name)
+	return s.save()
+}
+
+func (s *Storage) AddVehicleToRoute(name string, vehicleID int) (Route, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	r, ok := s.routes[name]
+	if !ok {
+		return Route{}, fmt.Errorf("route %s %w", name, ErrNotFound)
+	}
+	e, ok := s.vehicles[vehicleID]
+	if !ok {
+		return Route{}, fmt.Errorf("vehicle %d %w", vehicleID, ErrNotFound)
+	}
+	r.AddVehicleToRoute(e.vehicle)
+	s.routeVehicles[name] = append(s.routeVehicles[name], vehicleID)
+	return toRoute(r, s.routeVehicles[name]), s.save()
+}
+
+// BookTrip buys a ticket on the route for the passenger, creating the
+// passenger on first use.
+func (s *Storage) BookTrip(passengerID int, req TripRequest) (ticket.Ticket, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	r, ok := s.routes[req.Route]
+	if !ok {
+		return ticket.Ticket{}, fmt.Errorf("route %s %w", req.Route, ErrNotFound)
+	}
+	p, ok := s.passengers[passengerID]
+	if !ok {
+		p = &passengers.Passenger{Name: fmt.Sprintf("passenger %d", passengerID)}
+		s.passengers[passengerID] = p
+	}
+	if req.Name != "" {
+		p.Name = req.Name
+	}
+	p.Category, p.Class = req.Category, req.Class
+
+	t, err := p.BuyTicket(s.office, r)
+	if err != nil {
+		return ticket.Ticket{}, err
+	}
+	return t, s.save()
+}
+
+func (s *Storage) GetTrips(passengerID int) ([]ticket.Ticket, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	p, ok := s.passengers[passengerID]
+	if !ok {
+		return nil, fmt.Errorf("passenger %d %w", passengerID, ErrNotFound)
+	}
+	tickets := make([]ticket.Ticket, 0, len(p.Tickets))
+	for _, id := range p.Tickets {
+		if t, ok := s.office.GetTicket(id); ok {
+			tickets = append(tickets, t)
+		}
+	}
+	return tickets, nil
+}
+
+func (s *Storage) CancelTrip(passengerID int, ticketID int) (float64, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	p, ok := s.passengers[passengerID]
+	if !ok {
+		return 0, fmt.Errorf("passenger %d %w", passengerID, ErrNotFound)
+	}
+	refund, err := p.CancelTicket(s.office, ticketID)
+	if err != nil {
+		return 0, err
+	}
+	return refund, s.save()
+}
+
+// newVehicleEntry builds the vehicle with its saved passengers already on
+// board; it subscribes to the sink afterwards, so restoring isn't an event.
+func (s *Storage) newVehicleEntry(id int, v Vehicle) (*vehicleEntry, error) {
+	if v.Capacity <= 0 {
+		return nil, errors.New("vehicle capacity must be positive")
+	}
+	vehicle, err := publicTransport.New(v.Type, v.Name, v.Capacity)
+	if err != nil {
+		return nil, err
+	}
+	if v.Passengers > 0 {
+		if err := vehicle.AcceptPassenger(v.Passengers); err != nil {
+			return nil, err
+		}
+	}
+	if s.sink != nil {
+		vehicle.Subscribe(s.sink)
+	}
+	return &vehicleEntry{id: id, capacity: v.Capacity, vehicle: vehicle}, nil
+}
+
+// buildRoute rebuilds the route from its vehicle IDs. Like vehicles, it
+// subscribes to the sink only once it is restored.
+func (s *Storage) buildRoute(name string) *route.Route {
+	r := &route.Route{Name: name}
+	for _, id := range s.routeVehicles[name] {
+		r.AddVehicleToRoute(s.vehicles[id].vehicle)
+	}
+	if s.sink != nil {
+		r.Subscribe(s.sink)
+	}
+	return r
+}
+
+func (s *Storage) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("decoding %s: %w", s.path, err)
+	}
+
+	s.lastVehicleID = snap.LastVehicleID
+	for _, rec := range snap.Vehicles {
+		e, err := s.newVehicleEntry(rec.ID, Vehicle{
+			Type:       rec.Type,
+			Name:       rec.Name,
+			Capacity:   rec.Capacity,
+			Passengers: rec.Passengers,
+		})
+		if err != nil {
+			return fmt.Errorf("vehicle %d: %w", rec.ID, err)
+		}
+		s.vehicles[rec.ID] = e
+	}
+	for _, rec := range snap.Routes {
+		for _, id := range rec.VehicleIDs {
+			if _, ok := s.vehicles[id]; !ok {
+				return fmt.Errorf("route %s: vehicle %d %w", rec.Name, id, ErrNotFound)
+			}
+		}
+		s.routeVehicles[rec.Name] = rec.VehicleIDs
+		s.routes[rec.Name] = s.buildRoute(rec.Name)
+	}
+	return nil
+}
+
+func (s *Storage) save() error {
+	if err := s.writeSnapshot(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSave, err)
+	}
+	return nil
+}
+
+// writeSnapshot writes to a temporary file first, so a crash never leaves a
+// half-written file behind. Seats held by tickets aren't saved, since the
+// tickets themselves are lost on restart.
+func (s *Storage) writeSnapshot() error {
+	snap := snapshot{LastVehicleID: s.lastVehicleID}
+	for _, v := range s.vehicles {
+		snap.Vehicles = append(snap.Vehicles, vehicleRecord{
+			ID:         v.id,
+			Type:       v.vehicle.GetType(),
+			Name:       v.vehicle.GetName(),
+			Capacity:   v.capacity,
+			Passengers: v.vehicle.Passengers() - s.office.Reserved(v.vehicle),
+		})
+	}
+	sort.Slice(snap.Vehicles, func(i, j int) bool {
+		return snap.Vehicles[i].ID < snap.Vehicles[j].ID
+	})
+	for name, ids := range s.routeVehicles {
+		snap.Routes = append(snap.Routes, routeRecord{Name: name, VehicleIDs: ids})
+	}
+	sort.Slice(snap.Routes, func(i, j int) bool {
+		return snap.Routes[i].Name < snap.Routes[j].Name
+	})
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("saving %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	Boarded      Type = "boarded"
+	Denied       Type = "denied"
+	Alighted     Type = "alighted"
+	VehicleAdded Type = "vehicle_added"
+)
+
+type Event struct {
+	Time        time.Time `json:"time"`
+	Type        Type      `json:"type"`
+	Route       string    `json:"route,omitempty"`
+	VehicleType string    `json:"vehicleType"`
+	Vehicle     string    `json:"vehicle"`
+	Passengers  int       `json:"passengers,omitempty"`
+	OnBoard     int       `json:"onBoard"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+type Sink interface {
+	Emit(e Event)
+}
+
+// Observers is embedded by everything that emits events.
+type Observers struct {
+	m     sync.Mutex
+	sinks []Sink
+}
+
+func (o *Observers) Subscribe(sink Sink) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.sinks = append(o.sinks, sink)
+}
+
+func (o *Observers) Emit(e Event) {
+	o.m.Lock()
+	sinks := append([]Sink(nil), o.sinks...)
+	o.m.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, sink := range sinks {
+		sink.Emit(e)
+	}
+}
+
+// Console prints one human-readable line per event.
+type Console struct {
+	m sync.Mutex
+	w io.Writer
+}
+
+func NewConsole(w io.Writer) *Console {
+	return &Console{w: w}
+}
+
+func (c *Console) Emit(e Event) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	line := fmt.Sprintf("[%s] %s %s %s", e.Time.Format("15:04:05"), e.Type, e.VehicleType, e.Vehicle)
+	if e.Route != "" {
+		line += " on route " + e.Route
+	}
+	if e.Passengers > 0 {
+		line += fmt.Sprintf(", %d passengers", e.Passengers)
+	}
+	if e.Type != VehicleAdded {
+		line += fmt.Sprintf(", %d on board", e.OnBoard)
+	}
+	if e.Reason != "" {
+		line += ": " + e.Reason
+	}
+	fmt.Fprintln(c.w, line)
+}
+
+// JSONLines writes every event as one JSON object per line.
+type JSONLines struct {
+	m    sync.Mutex
+	enc  *json.Encoder
+	file *os.File
+}
+
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(w)}
+}
+
+// NewJSONLinesFile appends events to the file, creating it if needed.
+func NewJSONLinesFile(path string) (*JSONLines, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLines{enc: json.NewEncoder(file), file: file}, nil
+}
+
+func (j *JSONLines) Emit(e Event) {
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	if err := j.enc.Encode(e); err != nil {
+		fmt.Printf("Failed to encode event: %v\n", err)
+	}
+}
+
+func (j *JSONLines) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// Recorder keeps events in memory, for tests and summaries.
+type Recorder struct {
+	m      sync.Mutex
+	events []Event
+}
+
+func (r *Recorder) Emit(e Event) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+func (r *Recorder) Events() []Event {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
+
+func (r *Recorder) Count(t Type) int {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	count := 0
+	for _, e := range r.events {
+		if e.Type == t {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *Recorder) Reset() {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.events = nil
+}
+
+// Multi sends every event to all of its sinks.
+type Multi []Sink
+
+func (m Multi) Emit(e Event) {
+	for _, sink := range m {
+		sink.Emit(e)
+	}
+}
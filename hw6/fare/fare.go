@@ -0,0 +1,201 @@
+package fare
+
+import (
+	"GoLangProjector/hw6/route"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type Category string
+
+const (
+	Adult   Category = "adult"
+	Student Category = "student"
+	Senior  Category = "senior"
+	Child   Category = "child"
+)
+
+type Class string
+
+const (
+	Economy  Class = "economy"
+	Business Class = "business"
+)
+
+// Rule prices a single leg before any discount.
+type Rule interface {
+	Price(leg route.Leg, class Class) float64
+}
+
+type Flat struct {
+	Amount float64
+}
+
+func (f Flat) Price(leg route.Leg, class Class) float64 {
+	return f.Amount
+}
+
+// PerKm charges Base plus Rate for every kilometre, but never less than Min.
+type PerKm struct {
+	Base float64
+	Rate float64
+	Min  float64
+}
+
+func (p PerKm) Price(leg route.Leg, class Class) float64 {
+	return max(p.Base+p.Rate*leg.Distance, p.Min)
+}
+
+// ByClass has a price for every travel class; unknown classes pay Economy.
+type ByClass map[Class]float64
+
+func (b ByClass) Price(leg route.Leg, class Class) float64 {
+	if price, ok := b[class]; ok {
+		return price
+	}
+	return b[Economy]
+}
+
+type Item struct {
+	Vehicle          string  `json:"vehicle"`
+	From             string  `json:"from,omitempty"`
+	To               string  `json:"to,omitempty"`
+	Base             float64 `json:"base"`
+	CategoryDiscount float64 `json:"categoryDiscount"`
+	TransferDiscount float64 `json:"transferDiscount"`
+	Total            float64 `json:"total"`
+}
+
+type Breakdown struct {
+	Route    string   `json:"route"`
+	Category Category `json:"category"`
+	Class    Class    `json:"class"`
+	Items    []Item   `json:"items"`
+	Total    float64  `json:"total"`
+}
+
+func (b Breakdown) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Fare for route %s (%s, %s):\n", b.Route, b.Category, b.Class)
+	for i, item := range b.Items {
+		fmt.Fprintf(&sb, "%d. %-22s %8.2f", i+1, item.Vehicle, item.Base)
+		if item.CategoryDiscount > 0 {
+			fmt.Fprintf(&sb, "  -%.2f %s", item.CategoryDiscount, b.Category)
+		}
+		if item.TransferDiscount > 0 {
+			fmt.Fprintf(&sb, "  -%.2f transfer", item.TransferDiscount)
+		}
+		fmt.Fprintf(&sb, "  = %.2f\n", item.Total)
+	}
+	fmt.Fprintf(&sb, "Total: %.2f\n", b.Total)
+	return sb.String()
+}
+
+type Engine struct {
+	byType    map[string]Rule
+	byRoute   map[string]map[string]Rule
+	discounts map[Category]float64
+	// TransferDiscount is the share taken off a leg that departs within
+	// TransferWindow after the previous leg arrives.
+	TransferDiscount float64
+	TransferWindow   time.Duration
+}
+
+// NewEngine returns an engine with the default fares: flat fares for city
+// transport, distance-based train fares and class-based airplane fares.
+func NewEngine() *Engine {
+	return &Engine{
+		byType: map[string]Rule{
+			"bus":      Flat{Amount: 25},
+			"tram":     Flat{Amount: 15},
+			"metro":    Flat{Amount: 15},
+			"ferry":    Flat{Amount: 100},
+			"train":    PerKm{Base: 50, Rate: 1.2, Min: 80},
+			"airplane": ByClass{Economy: 2000, Business: 5000},
+		},
+		byRoute: make(map[string]map[string]Rule),
+		discounts: map[Category]float64{
+			Student: 0.3,
+			Senior:  0.4,
+			Child:   0.5,
+		},
+		TransferDiscount: 0.5,
+		TransferWindow:   90 * time.Minute,
+	}
+}
+
+func (e *Engine) SetRule(vehicleType string, rule Rule) {
+	e.byType[vehicleType] = rule
+}
+
+// SetRouteRule overrides the rule of one vehicle type on one route only.
+func (e *Engine) SetRouteRule(routeName string, vehicleType string, rule Rule) {
+	if e.byRoute[routeName] == nil {
+		e.byRoute[routeName] = make(map[string]Rule)
+	}
+	e.byRoute[routeName][vehicleType] = rule
+}
+
+func (e *Engine) SetDiscount(category Category, discount float64) {
+	e.discounts[category] = discount
+}
+
+// Calculate prices every leg of the route. Route rules are looked up by the
+// route each leg came from, so a planned journey keeps their overrides. The
+// category discount applies to each leg, the transfer discount to what is
+// left of it afterwards.
+func (e *Engine) Calculate(r *route.Route, category Category, class Class) (Breakdown, error) {
+	if category == "" {
+		category = Adult
+	}
+	if class == "" {
+		class = Economy
+	}
+	b := Breakdown{Route: r.Name, Category: category, Class: class}
+
+	for i, leg := range r.Legs {
+		vehicleType := leg.Vehicle.GetType()
+		routeName := leg.Route
+		if routeName == "" {
+			routeName = r.Name
+		}
+		rule, ok := e.byRoute[routeName][vehicleType]
+		if !ok {
+			rule, ok = e.byType[vehicleType]
+		}
+		if !ok {
+			return Breakdown{}, fmt.Errorf("no fare rule for %s", vehicleType)
+		}
+
+		item := Item{
+			Vehicle: vehicleType + " " + leg.Vehicle.GetName(),
+			Base:    round(rule.Price(leg, class)),
+		}
+		if leg.From != nil && leg.To != nil {
+			item.From, item.To = leg.From.Name, leg.To.Name
+		}
+		item.CategoryDiscount = round(item.Base * e.discounts[category])
+		if i > 0 && e.isTransfer(r.Legs[i-1], leg) {
+			item.TransferDiscount = round((item.Base - item.CategoryDiscount) * e.TransferDiscount)
+		}
+		item.Total = item.Base - item.CategoryDiscount - item.TransferDiscount
+		b.Items = append(b.Items, item)
+		b.Total += item.Total
+	}
+	b.Total = round(b.Total)
+	return b, nil
+}
+
+func (e *Engine) isTransfer(prev route.Leg, next route.Leg) bool {
+	if prev.To == nil || next.From == nil {
+		return false
+	}
+	window := next.Departure - prev.Arrival
+	return window >= 0 && window <= e.TransferWindow
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
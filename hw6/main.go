@@ -1,35 +1,166 @@
 package main
 
 import (
+	"GoLangProjector/hw6/api"
+	"GoLangProjector/hw6/events"
+	"GoLangProjector/hw6/fare"
 	"GoLangProjector/hw6/passengers"
+	"GoLangProjector/hw6/planner"
 	"GoLangProjector/hw6/publicTransport"
 	"GoLangProjector/hw6/route"
+	"GoLangProjector/hw6/simulation"
+	"GoLangProjector/hw6/ticket"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
 )
 
 func main() {
+	addr := flag.String("serve", "", "serve the REST API on this address instead of running the demo, e.g. :8080")
+	dataPath := flag.String("data", "transport.json", "file where the REST API keeps vehicles and routes")
+	eventsPath := flag.String("events", "", "also append vehicle and route events as JSON lines to this file")
+	flag.Parse()
 
-	bus32 := &publicTransport.Bus{Name: "№ 32", Capacity: 30, NumberPassengers: 25}
-	bus66 := &publicTransport.Bus{Name: "№ 66", Capacity: 30, NumberPassengers: 5}
-	train := &publicTransport.Train{Name: "Kyiv-Lviv", Capacity: 100, NumberPassengers: 99}
-	airplane := &publicTransport.Airplane{Name: "Lviv-Dresden", Capacity: 200, NumberPassengers: 200}
+	recorder := &events.Recorder{}
+	sink := events.Multi{events.NewConsole(os.Stdout), recorder}
+	if *eventsPath != "" {
+		jsonLines, err := events.NewJSONLinesFile(*eventsPath)
+		if err != nil {
+			fmt.Println("Error opening events file: ", err.Error())
+			return
+		}
+		defer jsonLines.Close()
+		sink = append(sink, jsonLines)
+	}
+
+	if *addr != "" {
+		storage, err := api.NewStorage(*dataPath, ticket.NewOffice(fare.NewEngine()), sink)
+		if err != nil {
+			fmt.Println("Error loading state: ", err.Error())
+			return
+		}
+		mux := http.NewServeMux()
+		api.NewResource(storage).Register(mux)
+
+		if err := http.ListenAndServe(*addr, mux); err != nil {
+			fmt.Println("Error is occurred: ", err.Error())
+		}
+		return
+	}
+
+	bus32 := publicTransport.NewBus("№ 32", 30)
+	bus32.NumberPassengers = 25
+	bus66 := publicTransport.NewBus("№ 66", 30)
+	bus66.NumberPassengers = 5
+	train := publicTransport.NewTrain("Kyiv-Lviv", 100)
+	train.NumberPassengers = 99
+	airplane := publicTransport.NewAirplane("Lviv-Dresden", 200)
+	airplane.NumberPassengers = 200
+
+	tram, err := publicTransport.New("tram", "№ 1", 40)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+
+	for _, vehicle := range []publicTransport.PublicTransport{bus32, bus66, train, airplane, tram} {
+		vehicle.Subscribe(sink)
+	}
 
 	routeA := &route.Route{Name: "A"}
+	routeA.Subscribe(sink)
+	routeA.AddVehicleToRoute(tram)
 	routeA.AddVehicleToRoute(bus32)
 	routeA.AddVehicleToRoute(train)
 	routeA.AddVehicleToRoute(bus66)
 
 	routeB := &route.Route{Name: "B"}
+	routeB.Subscribe(sink)
 	routeB.AddVehicleToRoute(bus66)
 	routeB.AddVehicleToRoute(airplane)
 	routeB.AddVehicleToRoute(train)
 
+	fares := fare.NewEngine()
+	fares.SetRouteRule("C", "bus", fare.PerKm{Base: 200, Rate: 0.8, Min: 300})
+	office := ticket.NewOffice(fares)
+
 	routeA.ShowListOfVehiclesOnRoute()
 
-	passenger1 := &passengers.Passenger{Name: "John Doe"}
-	passenger1.CompleteRoute(routeA)
+	passenger1 := &passengers.Passenger{Name: "John Doe", Category: fare.Student}
+	if breakdown, err := passenger1.CompleteRoute(office, routeA, nil); err == nil {
+		fmt.Print(breakdown)
+	}
 
 	routeB.ShowListOfVehiclesOnRoute()
 
 	passenger2 := &passengers.Passenger{Name: "Tom Lee"}
-	passenger2.CompleteRoute(routeB)
+	passenger2.CompleteRoute(office, routeB, nil)
+
+	passenger3 := &passengers.Passenger{Name: "Ann Cole"}
+	t, err := passenger3.BuyTicket(office, routeA)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	fmt.Printf("%s booked ticket №%d on route %s, %s has %d passengers\n", passenger3.Name, t.ID, t.Route, train.GetName(), train.Passengers())
+	refund, err := passenger3.CancelTicket(office, t.ID)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	fmt.Printf("%s cancelled ticket №%d, refund %.2f, %s has %d passengers\n", passenger3.Name, t.ID, refund, train.GetName(), train.Passengers())
+
+	tt, err := route.LoadTimetable("timetable")
+	if err != nil {
+		fmt.Println("Error loading timetable: ", err.Error())
+		return
+	}
+	for _, r := range tt.Routes {
+		r.ShowListOfVehiclesOnRoute()
+	}
+
+	pl := planner.New(tt.Routes)
+	for _, preference := range []planner.Preference{planner.Fastest, planner.FewestTransfers} {
+		journey, err := pl.Plan(tt.Stops["kyiv_center"], tt.Stops["dresden_airport"], 7*time.Hour+30*time.Minute, preference)
+		if err != nil {
+			fmt.Println("Error is occurred: ", err.Error())
+			continue
+		}
+		fmt.Printf("Journey with %d transfers:\n", journey.Transfers)
+		journey.Route("Kyiv-Dresden").ShowListOfVehiclesOnRoute()
+	}
+
+	plane := tt.Trips["plane_1800"].Vehicle
+	if err := plane.AcceptPassenger(plane.FreeSeats()); err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+	}
+	routeTTB, _ := tt.GetRoute("B")
+	passenger4 := &passengers.Passenger{Name: "Eva Klein", Category: fare.Senior, Class: fare.Business}
+	if breakdown, err := passenger4.CompleteRoute(office, routeTTB, pl); err == nil {
+		fmt.Print(breakdown)
+	}
+
+	routeTTA, _ := tt.GetRoute("A")
+	passenger5 := &passengers.Passenger{Name: "Max Bauer", Category: fare.Child}
+	if breakdown, err := passenger5.CompleteRoute(office, routeTTA, pl); err == nil {
+		fmt.Print(breakdown)
+	}
+
+	simTimetable, err := route.LoadTimetable("timetable")
+	if err != nil {
+		fmt.Println("Error loading timetable: ", err.Error())
+		return
+	}
+	sim := simulation.New(simTimetable.Routes, simulation.Config{
+		Demand:        map[string]float64{"A": 60},
+		DefaultDemand: 30,
+		Window:        time.Hour,
+		Seed:          1,
+	})
+	fmt.Print(sim.Run())
+
+	fmt.Printf("Events of the demo vehicles: %d boarded, %d denied, %d alighted\n",
+		recorder.Count(events.Boarded), recorder.Count(events.Denied), recorder.Count(events.Alighted))
 }
@@ -1,24 +1,66 @@
 package passengers
 
 import (
+	"GoLangProjector/hw6/fare"
+	"GoLangProjector/hw6/planner"
+	"GoLangProjector/hw6/publicTransport"
 	"GoLangProjector/hw6/route"
+	"GoLangProjector/hw6/ticket"
+	"errors"
 	"fmt"
+	"slices"
 )
 
 type Passenger struct {
-	Name string
+	Name     string
+	Category fare.Category
+	Class    fare.Class
+	Tickets  []int
 }
 
-func (p *Passenger) CompleteRoute(r *route.Route) {
+func (p *Passenger) BuyTicket(o *ticket.Office, r *route.Route) (ticket.Ticket, error) {
+	t, err := o.Buy(p.Name, p.Category, p.Class, r)
+	if err != nil {
+		return ticket.Ticket{}, err
+	}
+	p.Tickets = append(p.Tickets, t.ID)
+	return t, nil
+}
+
+func (p *Passenger) CancelTicket(o *ticket.Office, id int) (float64, error) {
+	if !slices.Contains(p.Tickets, id) {
+		return 0, ticket.ErrTicketNotFound
+	}
+	return o.Cancel(id)
+}
+
+// CompleteRoute books and travels the route. When a vehicle is full and the
+// route has a timetable, the planner looks for another journey between the
+// same stops instead. pl may be nil. It returns the fare paid for the trip.
+func (p *Passenger) CompleteRoute(o *ticket.Office, r *route.Route, pl *planner.Planner) (fare.Breakdown, error) {
 	fmt.Printf("%s is traveling by route: %s\n", p.Name, r.Name)
-	for _, vehicle := range r.Vehicles {
-		fmt.Printf(" %s - %s.\n", vehicle.GetType(), vehicle.GetName())
-		if vehicle.AcceptPassenger(1) != true {
-			fmt.Printf("You cann't drive this route. \n Because the vehicle %s %s has no free seats. Choose another route.\n",
-				vehicle.GetType(), vehicle.GetName())
-			return
-		} else {
-			vehicle.DropOffPassenger(1)
+	t, err := p.BuyTicket(o, r)
+	if errors.Is(err, publicTransport.ErrFull) && pl != nil && r.IsScheduled() {
+		fmt.Printf(" %v, looking for another journey.\n", err)
+		first, last := r.Legs[0], r.Legs[len(r.Legs)-1]
+		var journey planner.Journey
+		journey, err = pl.Plan(first.From, last.To, first.Departure, planner.Fastest)
+		if err == nil {
+			r = journey.Route(r.Name + " (alternative)")
+			r.ShowListOfVehiclesOnRoute()
+			t, err = p.BuyTicket(o, r)
 		}
 	}
+	if err != nil {
+		fmt.Printf("You cann't drive this route. \n Because %v.\n", err)
+		return fare.Breakdown{}, err
+	}
+	fmt.Printf(" Ticket №%d, price %.2f\n", t.ID, t.Price)
+	for _, vehicle := range t.Vehicles {
+		fmt.Printf(" %s.\n", vehicle)
+	}
+	if err := o.Use(t.ID); err != nil {
+		return t.Fare, err
+	}
+	return t.Fare, nil
 }
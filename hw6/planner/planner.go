@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"GoLangProjector/hw6/route"
+	"container/heap"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoJourney = errors.New("no journey found")
+
+type Preference int
+
+const (
+	Fastest Preference = iota
+	FewestTransfers
+)
+
+type Journey struct {
+	Legs      []route.Leg
+	Transfers int
+	Departure time.Duration
+	Arrival   time.Duration
+}
+
+type Planner struct {
+	connections []route.Leg
+	byStop      map[*route.Stop][]int
+}
+
+// New collects the scheduled legs of every route. A leg shared by several
+// routes is only used once, with the route it was found on first.
+func New(routes []*route.Route) *Planner {
+	p := &Planner{byStop: make(map[*route.Stop][]int)}
+	seen := make(map[route.Leg]bool)
+	for _, r := range routes {
+		if !r.IsScheduled() {
+			continue
+		}
+		for _, leg := range r.Legs {
+			key := leg
+			key.Route = ""
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			p.byStop[leg.From] = append(p.byStop[leg.From], len(p.connections))
+			p.connections = append(p.connections, leg)
+		}
+	}
+	return p
+}
+
+type label struct {
+	connection int
+	prev       *label
+	transfers  int
+	arrival    time.Duration
+}
+
+type labelHeap struct {
+	labels     []*label
+	preference Preference
+}
+
+func (h labelHeap) Len() int { return len(h.labels) }
+func (h labelHeap) Less(i, j int) bool {
+	a, b := h.labels[i], h.labels[j]
+	if h.preference == FewestTransfers && a.transfers != b.transfers {
+		return a.transfers < b.transfers
+	}
+	if a.arrival != b.arrival {
+		return a.arrival < b.arrival
+	}
+	return a.transfers < b.transfers
+}
+func (h labelHeap) Swap(i, j int) { h.labels[i], h.labels[j] = h.labels[j], h.labels[i] }
+func (h *labelHeap) Push(x any)   { h.labels = append(h.labels, x.(*label)) }
+func (h *labelHeap) Pop() any {
+	old := h.labels
+	l := old[len(old)-1]
+	h.labels = old[:len(old)-1]
+	return l
+}
+
+// Plan finds a journey from one stop to another leaving no earlier than
+// departure. Full vehicles are skipped, and changing vehicles needs at least
+// the minimum transfer time of the stop plus the time it takes to board the
+// next vehicle, like lowering a ferry's gangway.
+func (p *Planner) Plan(from *route.Stop, to *route.Stop, departure time.Duration, preference Preference) (Journey, error) {
+	if from == to {
+		return Journey{Departure: departure, Arrival: departure}, nil
+	}
+	h := &labelHeap{preference: preference}
+	visited := make([]bool, len(p.connections))
+
+	for _, c := range p.byStop[from] {
+		leg := p.connections[c]
+		if leg.Departure >= departure && leg.Vehicle.FreeSeats() > 0 {
+			heap.Push(h, &label{connection: c, arrival: leg.Arrival})
+		}
+	}
+
+	for h.Len() > 0 {
+		l := heap.Pop(h).(*label)
+		if visited[l.connection] {
+			continue
+		}
+		visited[l.connection] = true
+
+		current := p.connections[l.connection]
+		if current.To == to {
+			return p.journey(l), nil
+		}
+		for _, c := range p.byStop[current.To] {
+			next := p.connections[c]
+			if visited[c] {
+				continue
+			}
+			sameVehicle := next.Vehicle == current.Vehicle
+			ready := current.Arrival
+			transfers := l.transfers
+			if !sameVehicle {
+				ready += current.To.MinTransfer + next.Vehicle.BoardingTime(1)
+				transfers++
+				if next.Vehicle.FreeSeats() <= 0 {
+					continue
+				}
+			}
+			if next.Departure < ready {
+				continue
+			}
+			heap.Push(h, &label{connection: c, prev: l, transfers: transfers, arrival: next.Arrival})
+		}
+	}
+	return Journey{}, fmt.Errorf("%w from %s to %s after %s", ErrNoJourney, from.Name, to.Name, route.FormatClock(departure))
+}
+
+// journey walks the labels back to the origin and joins consecutive legs on
+// the same vehicle into one.
+func (p *Planner) journey(l *label) Journey {
+	var legs []route.Leg
+	for ; l != nil; l = l.prev {
+		legs = append([]route.Leg{p.connections[l.connection]}, legs...)
+	}
+	var merged []route.Leg
+	for _, leg := range legs {
+		if n := len(merged); n > 0 && merged[n-1].Vehicle == leg.Vehicle {
+			merged[n-1].To = leg.To
+			merged[n-1].Arrival = leg.Arrival
+			merged[n-1].Distance += leg.Distance
+			continue
+		}
+		merged = append(merged, leg)
+	}
+	return Journey{
+		Legs:      merged,
+		Transfers: len(merged) - 1,
+		Departure: merged[0].Departure,
+		Arrival:   merged[len(merged)-1].Arrival,
+	}
+}
+
+// Route turns the journey into a route, so it can be booked like any other.
+func (j Journey) Route(name string) *route.Route {
+	r := &route.Route{Name: name}
+	for _, leg := range j.Legs {
+		r.AddLeg(leg)
+	}
+	return r
+}
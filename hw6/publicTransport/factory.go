@@ -0,0 +1,52 @@
+package publicTransport
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type Constructor func(name string, capacity int) PublicTransport
+
+var (
+	registryM sync.RWMutex
+	registry  = map[string]Constructor{
+		"bus":      func(name string, capacity int) PublicTransport { return NewBus(name, capacity) },
+		"train":    func(name string, capacity int) PublicTransport { return NewTrain(name, capacity) },
+		"airplane": func(name string, capacity int) PublicTransport { return NewAirplane(name, capacity) },
+		"tram":     func(name string, capacity int) PublicTransport { return NewTram(name, capacity) },
+		"metro":    func(name string, capacity int) PublicTransport { return NewMetro(name, capacity) },
+		"ferry":    func(name string, capacity int) PublicTransport { return NewFerry(name, capacity) },
+	}
+)
+
+// Register adds a new vehicle type or replaces an existing one.
+func Register(typeName string, constructor Constructor) {
+	registryM.Lock()
+	defer registryM.Unlock()
+
+	registry[typeName] = constructor
+}
+
+func New(typeName string, name string, capacity int) (PublicTransport, error) {
+	registryM.RLock()
+	constructor, ok := registry[typeName]
+	registryM.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown vehicle type %q", typeName)
+	}
+	return constructor(name, capacity), nil
+}
+
+func Types() []string {
+	registryM.RLock()
+	defer registryM.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for typeName := range registry {
+		types = append(types, typeName)
+	}
+	sort.Strings(types)
+	return types
+}
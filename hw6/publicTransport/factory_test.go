@@ -0,0 +1,100 @@
+package publicTransport
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+)
+
+// step is one random action on a vehicle: a group boards or, with Drop set,
+// leaves. Group sizes include zero and negative numbers on purpose.
+type step struct {
+	Drop  bool
+	Group int8
+}
+
+// TestVehicleProperties runs random boarding and alighting sequences against
+// every registered vehicle type and checks the passenger count after each step.
+func TestVehicleProperties(t *testing.T) {
+	for _, typeName := range Types() {
+		t.Run(typeName, func(t *testing.T) {
+			property := func(capacity uint8, steps []step) bool {
+				vehicle, err := New(typeName, "test", int(capacity)%50+1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				limit := vehicle.FreeSeats()
+
+				for i, s := range steps {
+					group := int(s.Group) % 20
+					before := vehicle.Passengers()
+					if s.Drop {
+						err = vehicle.DropOffPassenger(group)
+					} else {
+						err = vehicle.AcceptPassenger(group)
+					}
+					after := vehicle.Passengers()
+
+					if after < 0 || after > limit {
+						t.Logf("step %d: %d on board, room for %d", i, after, limit)
+						return false
+					}
+					if err != nil {
+						if after != before {
+							t.Logf("step %d: failed with %v but the count went from %d to %d", i, err, before, after)
+							return false
+						}
+						if !expectedError(err, s.Drop, group, before, limit) {
+							t.Logf("step %d: unexpected error %v for group %d with %d on board", i, err, group, before)
+							return false
+						}
+						continue
+					}
+
+					want := before + group
+					if s.Drop {
+						want = before - group
+					}
+					if after != want {
+						t.Logf("step %d: group of %d moved the count from %d to %d", i, group, before, after)
+						return false
+					}
+					if vehicle.FreeSeats() != limit-after {
+						t.Logf("step %d: %d free seats with %d of %d on board", i, vehicle.FreeSeats(), after, limit)
+						return false
+					}
+
+					if !s.Drop {
+						if err := vehicle.DropOffPassenger(group); err != nil || vehicle.Passengers() != before {
+							t.Logf("step %d: dropping the group of %d left %d on board, want %d (%v)",
+								i, group, vehicle.Passengers(), before, err)
+							return false
+						}
+						if err := vehicle.AcceptPassenger(group); err != nil {
+							t.Logf("step %d: group of %d can't board again: %v", i, group, err)
+							return false
+						}
+					}
+				}
+				return true
+			}
+			if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// expectedError tells whether err is the one the step should fail with:
+// ErrInvalidPassengers for empty groups, ErrFull when the group doesn't fit
+// and ErrNotEnoughPassengers when more leave than are on board.
+func expectedError(err error, drop bool, group int, onBoard int, limit int) bool {
+	switch {
+	case group <= 0:
+		return errors.Is(err, ErrInvalidPassengers)
+	case drop:
+		return group > onBoard && errors.Is(err, ErrNotEnoughPassengers)
+	default:
+		return onBoard+group > limit && errors.Is(err, ErrFull)
+	}
+}
@@ -0,0 +1,22 @@
+package publicTransport
+
+import "time"
+
+// Ferry has seats only and needs the gangway lowered before anyone boards.
+type Ferry struct {
+	Vehicle
+	GangwayTime time.Duration
+}
+
+func NewFerry(name string, capacity int) *Ferry {
+	f := &Ferry{GangwayTime: 5 * time.Minute}
+	f.init("ferry", name, capacity, 4*time.Second)
+	return f
+}
+
+func (f *Ferry) BoardingTime(passengers int) time.Duration {
+	if passengers <= 0 {
+		return 0
+	}
+	return f.GangwayTime + f.Vehicle.BoardingTime(passengers)
+}
@@ -0,0 +1,23 @@
+package publicTransport
+
+import "time"
+
+// Metro takes twice as many standing passengers as seats and boards fastest.
+type Metro struct {
+	Vehicle
+	StandingCapacity int
+}
+
+func NewMetro(name string, capacity int) *Metro {
+	m := &Metro{StandingCapacity: 2 * capacity}
+	m.init("metro", name, capacity, time.Second)
+	return m
+}
+
+func (m *Metro) AcceptPassenger(passengers int) error {
+	return m.accept(passengers, m.Capacity+m.StandingCapacity)
+}
+
+func (m *Metro) FreeSeats() int {
+	return m.freeSeats(m.Capacity + m.StandingCapacity)
+}
@@ -1,8 +1,17 @@
 package publicTransport
 
+import (
+	"GoLangProjector/hw6/events"
+	"time"
+)
+
 type PublicTransport interface {
-	AcceptPassenger(passengers int) bool
-	DropOffPassenger(passengers int)
+	AcceptPassenger(passengers int) error
+	DropOffPassenger(passengers int) error
+	FreeSeats() int
+	Passengers() int
+	BoardingTime(passengers int) time.Duration
 	GetName() string
 	GetType() string
+	Subscribe(sink events.Sink)
 }
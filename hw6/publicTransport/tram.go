@@ -0,0 +1,23 @@
+package publicTransport
+
+import "time"
+
+// Tram takes standing passengers on top of its seats.
+type Tram struct {
+	Vehicle
+	StandingCapacity int
+}
+
+func NewTram(name string, capacity int) *Tram {
+	t := &Tram{StandingCapacity: capacity}
+	t.init("tram", name, capacity, 2*time.Second)
+	return t
+}
+
+func (t *Tram) AcceptPassenger(passengers int) error {
+	return t.accept(passengers, t.Capacity+t.StandingCapacity)
+}
+
+func (t *Tram) FreeSeats() int {
+	return t.freeSeats(t.Capacity + t.StandingCapacity)
+}
@@ -0,0 +1,143 @@
+package publicTransport
+
+import (
+	"GoLangProjector/hw6/events"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrFull                = errors.New("vehicle is full")
+	ErrNotEnoughPassengers = errors.New("not enough passengers on board")
+	ErrInvalidPassengers   = errors.New("number of passengers must be positive")
+)
+
+// Vehicle holds the passenger counting shared by every kind of transport.
+// Concrete vehicles embed it and override only the rules that differ.
+// The same vehicle can be on several routes, so every method that reads or
+// changes NumberPassengers holds m. Set NumberPassengers directly only
+// before the vehicle is shared.
+type Vehicle struct {
+	m                sync.Mutex
+	Name             string
+	Capacity         int
+	NumberPassengers int
+	kind             string
+	boardingTime     time.Duration
+	observers        events.Observers
+}
+
+func (v *Vehicle) init(kind string, name string, capacity int, boardingTime time.Duration) {
+	v.Name = name
+	v.Capacity = capacity
+	v.kind = kind
+	v.boardingTime = boardingTime
+}
+
+// AcceptPassenger boards the whole group or, if it doesn't fit, nobody.
+func (v *Vehicle) AcceptPassenger(passengers int) error {
+	return v.accept(passengers, v.Capacity)
+}
+
+// accept boards the group and then tells the observers, outside the lock, so
+// a slow sink never holds up other passengers.
+func (v *Vehicle) accept(passengers int, limit int) error {
+	onBoard, err := v.board(passengers, limit)
+	e := v.event(events.Boarded, passengers, onBoard)
+	if err != nil {
+		e.Type, e.Reason = events.Denied, err.Error()
+	}
+	v.observers.Emit(e)
+	return err
+}
+
+func (v *Vehicle) board(passengers int, limit int) (int, error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if passengers <= 0 {
+		return v.NumberPassengers, fmt.Errorf("%w: %d", ErrInvalidPassengers, passengers)
+	}
+	if v.NumberPassengers+passengers > limit {
+		return v.NumberPassengers, fmt.Errorf("%w: %s %s has %d free seats, %d passengers can't board",
+			ErrFull, v.kind, v.Name, max(limit-v.NumberPassengers, 0), passengers)
+	}
+	v.NumberPassengers += passengers
+	return v.NumberPassengers, nil
+}
+
+// DropOffPassenger lets the group leave. It fails without changing anything
+// when fewer passengers than that are on board.
+func (v *Vehicle) DropOffPassenger(passengers int) error {
+	onBoard, err := v.alight(passengers)
+	if err == nil {
+		v.observers.Emit(v.event(events.Alighted, passengers, onBoard))
+	}
+	return err
+}
+
+func (v *Vehicle) alight(passengers int) (int, error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if passengers <= 0 {
+		return v.NumberPassengers, fmt.Errorf("%w: %d", ErrInvalidPassengers, passengers)
+	}
+	if passengers > v.NumberPassengers {
+		return v.NumberPassengers, fmt.Errorf("%w: %s %s has %d passengers, %d can't leave",
+			ErrNotEnoughPassengers, v.kind, v.Name, v.NumberPassengers, passengers)
+	}
+	v.NumberPassengers -= passengers
+	return v.NumberPassengers, nil
+}
+
+// Subscribe sends the boarded, denied and alighted events of the vehicle to sink.
+func (v *Vehicle) Subscribe(sink events.Sink) {
+	v.observers.Subscribe(sink)
+}
+
+func (v *Vehicle) event(t events.Type, passengers int, onBoard int) events.Event {
+	return events.Event{
+		Type:        t,
+		VehicleType: v.kind,
+		Vehicle:     v.Name,
+		Passengers:  passengers,
+		OnBoard:     onBoard,
+	}
+}
+
+func (v *Vehicle) FreeSeats() int {
+	return v.freeSeats(v.Capacity)
+}
+
+func (v *Vehicle) Passengers() int {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	return v.NumberPassengers
+}
+
+func (v *Vehicle) freeSeats(limit int) int {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.NumberPassengers >= limit {
+		return 0
+	}
+	return limit - v.NumberPassengers
+}
+
+// BoardingTime is how long it takes the given number of passengers to get on.
+func (v *Vehicle) BoardingTime(passengers int) time.Duration {
+	return time.Duration(passengers) * v.boardingTime
+}
+
+func (v *Vehicle) GetName() string {
+	return v.Name
+}
+
+func (v *Vehicle) GetType() string {
+	return v.kind
+}
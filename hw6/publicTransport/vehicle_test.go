@@ -0,0 +1,47 @@
+package publicTransport
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestBoardConcurrently lets every passenger board from their own goroutine
+// and fails if the vehicle ever takes more passengers than it has room for.
+// Run with "go test -race" to let the race detector watch it too.
+func TestBoardConcurrently(t *testing.T) {
+	const capacity, passengers = 30, 100
+
+	for _, typeName := range Types() {
+		t.Run(typeName, func(t *testing.T) {
+			vehicle, err := New(typeName, "№ 7", capacity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			limit := vehicle.FreeSeats()
+
+			var wg sync.WaitGroup
+			var boarded atomic.Int64
+			for i := 0; i < passengers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if vehicle.AcceptPassenger(1) == nil {
+						boarded.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if vehicle.Passengers() > limit {
+				t.Fatalf("%s is overbooked: %d on board, room for %d", typeName, vehicle.Passengers(), limit)
+			}
+			if int(boarded.Load()) != vehicle.Passengers() {
+				t.Fatalf("%d passengers boarded, %d on board", boarded.Load(), vehicle.Passengers())
+			}
+			if want := min(passengers, limit); vehicle.Passengers() != want {
+				t.Fatalf("%d on board, want %d", vehicle.Passengers(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package route
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseClock parses a GTFS time of day, "HH:MM:SS" or "HH:MM". Hours may go
+// past 24 for trips that run after midnight.
+func ParseClock(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	var fields [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid time %q", value)
+		}
+		fields[i] = n
+	}
+	return time.Duration(fields[0])*time.Hour + time.Duration(fields[1])*time.Minute +
+		time.Duration(fields[2])*time.Second, nil
+}
+
+// FormatClock prints an offset from the start of the day as "HH:MM".
+func FormatClock(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%s%02d:%02d", sign, int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// FormatDuration prints a length of time as "2h15m", "1h", "35m" or, for
+// anything under half a minute, "0m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
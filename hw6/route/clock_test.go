@@ -0,0 +1,27 @@
+package route
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{20 * time.Second, "0m"},
+		{40 * time.Second, "1m"},
+		{35 * time.Minute, "35m"},
+		{time.Hour, "1h"},
+		{2*time.Hour + 15*time.Minute, "2h15m"},
+		{26*time.Hour + 5*time.Minute + 10*time.Second, "26h5m"},
+		{-30 * time.Minute, "-30m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package route
+
+import (
+	"GoLangProjector/hw6/publicTransport"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type StopTime struct {
+	Stop      *Stop
+	Sequence  int
+	Arrival   time.Duration
+	Departure time.Duration
+	// Distance is how far the trip has gone by this stop, in kilometres.
+	Distance float64
+}
+
+// Trip is one run of a vehicle through its stops.
+type Trip struct {
+	ID        string
+	Vehicle   publicTransport.PublicTransport
+	StopTimes []StopTime
+}
+
+type Timetable struct {
+	Stops  map[string]*Stop
+	Trips  map[string]*Trip
+	Routes []*Route
+}
+
+// LoadTimetable reads a GTFS-like set of CSV files from dir:
+//
+//	stops.txt       stop_id,stop_name
+//	transfers.txt   from_stop_id,min_transfer_time (seconds, optional file)
+//	trips.txt       trip_id,vehicle_type,vehicle_name,capacity
+//	stop_times.txt  trip_id,arrival_time,departure_time,stop_id,stop_sequence,
+//	                shape_dist_traveled (kilometres, optional column)
+//	route_legs.txt  route_name,leg_sequence,trip_id,from_stop_id,to_stop_id
+//
+// Columns are matched by header name, so their order doesn't matter.
+func LoadTimetable(dir string) (*Timetable, error) {
+	tt := &Timetable{
+		Stops: make(map[string]*Stop),
+		Trips: make(map[string]*Trip),
+	}
+	steps := []struct {
+		file     string
+		optional bool
+		load     func(row map[string]string) error
+	}{
+		{"stops.txt", false, tt.loadStop},
+		{"transfers.txt", true, tt.loadTransfer},
+		{"trips.txt", false, tt.loadTrip},
+		{"stop_times.txt", false, tt.loadStopTime},
+	}
+	for _, step := range steps {
+		err := readCSV(filepath.Join(dir, step.file), step.load)
+		if step.optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, trip := range tt.Trips {
+		sort.Slice(trip.StopTimes, func(i, j int) bool {
+			return trip.StopTimes[i].Sequence < trip.StopTimes[j].Sequence
+		})
+	}
+
+	if err := tt.loadRoutes(filepath.Join(dir, "route_legs.txt")); err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+func (tt *Timetable) GetRoute(name string) (*Route, bool) {
+	for _, r := range tt.Routes {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+func (tt *Timetable) loadStop(row map[string]string) error {
+	id := row["stop_id"]
+	if id == "" {
+		return errors.New("stop without stop_id")
+	}
+	tt.Stops[id] = &Stop{ID: id, Name: row["stop_name"]}
+	return nil
+}
+
+func (tt *Timetable) loadTransfer(row map[string]string) error {
+	stop, ok := tt.Stops[row["from_stop_id"]]
+	if !ok {
+		return fmt.Errorf("transfer at unknown stop %q", row["from_stop_id"])
+	}
+	seconds, err := strconv.Atoi(row["min_transfer_time"])
+	if err != nil {
+		return fmt.Errorf("invalid min_transfer_time %q", row["min_transfer_time"])
+	}
+	stop.MinTransfer = time.Duration(seconds) * time.Second
+	return nil
+}
+
+func (tt *Timetable) loadTrip(row map[string]string) error {
+	capacity, err := strconv.Atoi(row["capacity"])
+	if err != nil {
+		return fmt.Errorf("trip %s: invalid capacity %q", row["trip_id"], row["capacity"])
+	}
+	vehicle, err := publicTransport.New(row["vehicle_type"], row["vehicle_name"], capacity)
+	if err != nil {
+		return fmt.Errorf("trip %s: %w", row["trip_id"], err)
+	}
+	tt.Trips[row["trip_id"]] = &Trip{ID: row["trip_id"], Vehicle: vehicle}
+	return nil
+}
+
+func (tt *Timetable) loadStopTime(row map[string]string) error {
+	trip, ok := tt.Trips[row["trip_id"]]
+	if !ok {
+		return fmt.Errorf("stop time for unknown trip %q", row["trip_id"])
+	}
+	stop, ok := tt.Stops[row["stop_id"]]
+	if !ok {
+		return fmt.Errorf("trip %s: unknown stop %q", trip.ID, row["stop_id"])
+	}
+	sequence, err := strconv.Atoi(row["stop_sequence"])
+	if err != nil {
+		return fmt.Errorf("trip %s: invalid stop_sequence %q", trip.ID, row["stop_sequence"])
+	}
+	arrival, err := ParseClock(row["arrival_time"])
+	if err != nil {
+		return fmt.Errorf("trip %s: %w", trip.ID, err)
+	}
+	departure, err := ParseClock(row["departure_time"])
+	if err != nil {
+		return fmt.Errorf("trip %s: %w", trip.ID, err)
+	}
+	var distance float64
+	if row["shape_dist_traveled"] != "" {
+		distance, err = strconv.ParseFloat(row["shape_dist_traveled"], 64)
+		if err != nil {
+			return fmt.Errorf("trip %s: invalid shape_dist_traveled %q", trip.ID, row["shape_dist_traveled"])
+		}
+	}
+	trip.StopTimes = append(trip.StopTimes, StopTime{
+		Stop:      stop,
+		Sequence:  sequence,
+		Arrival:   arrival,
+		Departure: departure,
+		Distance:  distance,
+	})
+	return nil
+}
+
+func (tt *Timetable) loadRoutes(path string) error {
+	type routeLeg struct {
+		sequence int
+		leg      Leg
+	}
+	legsByRoute := make(map[string][]routeLeg)
+	var names []string
+
+	err := readCSV(path, func(row map[string]string) error {
+		name := row["route_name"]
+		sequence, err := strconv.Atoi(row["leg_sequence"])
+		if err != nil {
+			return fmt.Errorf("route %s: invalid leg_sequence %q", name, row["leg_sequence"])
+		}
+		trip, ok := tt.Trips[row["trip_id"]]
+		if !ok {
+			return fmt.Errorf("route %s: unknown trip %q", name, row["trip_id"])
+		}
+		leg, err := trip.Leg(row["from_stop_id"], row["to_stop_id"])
+		if err != nil {
+			return fmt.Errorf("route %s: %w", name, err)
+		}
+		if _, ok := legsByRoute[name]; !ok {
+			names = append(names, name)
+		}
+		legsByRoute[name] = append(legsByRoute[name], routeLeg{sequence: sequence, leg: leg})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		legs := legsByRoute[name]
+		sort.Slice(legs, func(i, j int) bool {
+			return legs[i].sequence < legs[j].sequence
+		})
+		r := &Route{Name: name}
+		for _, l := range legs {
+			r.AddLeg(l.leg)
+		}
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		tt.Routes = append(tt.Routes, r)
+	}
+	return nil
+}
+
+// Leg is the ride on this trip between two of its stops.
+func (t *Trip) Leg(fromStopID string, toStopID string) (Leg, error) {
+	from, to := -1, -1
+	for i, st := range t.StopTimes {
+		if st.Stop.ID == fromStopID && from < 0 {
+			from = i
+		}
+		if st.Stop.ID == toStopID && from >= 0 && i > from && to < 0 {
+			to = i
+		}
+	}
+	if from < 0 || to <= from {
+		return Leg{}, fmt.Errorf("trip %s doesn't go from %q to %q", t.ID, fromStopID, toStopID)
+	}
+	return Leg{
+		Vehicle:   t.Vehicle,
+		From:      t.StopTimes[from].Stop,
+		To:        t.StopTimes[to].Stop,
+		Departure: t.StopTimes[from].Departure,
+		Arrival:   t.StopTimes[to].Arrival,
+		Distance:  t.StopTimes[to].Distance - t.StopTimes[from].Distance,
+	}, nil
+}
+
+// readCSV calls load for every row of the file, keyed by the header names.
+func readCSV(path string, load func(row map[string]string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	header := records[0]
+	for i, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for j, name := range header {
+			if j < len(record) {
+				row[name] = record[j]
+			}
+		}
+		if err := load(row); err != nil {
+			return fmt.Errorf("%s line %d: %w", filepath.Base(path), i+2, err)
+		}
+	}
+	return nil
+}
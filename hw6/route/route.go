@@ -1,22 +1,142 @@
 package route
 
 import (
+	"GoLangProjector/hw6/events"
 	"GoLangProjector/hw6/publicTransport"
 	"fmt"
+	"time"
 )
 
+type Stop struct {
+	ID          string
+	Name        string
+	MinTransfer time.Duration
+}
+
+// Leg is one ride of a route. Departure and Arrival are offsets from the
+// start of the service day; legs added without a schedule have nil stops.
+// Distance is in kilometres, zero when unknown. Route names the route the
+// leg was added to first, so a journey built from legs of several routes
+// still knows where each of them came from.
+type Leg struct {
+	Route     string
+	Vehicle   publicTransport.PublicTransport
+	From      *Stop
+	To        *Stop
+	Departure time.Duration
+	Arrival   time.Duration
+	Distance  float64
+}
+
+type Transfer struct {
+	At          *Stop
+	Arrival     time.Duration
+	Departure   time.Duration
+	Window      time.Duration
+	MinTransfer time.Duration
+}
+
 type Route struct {
-	Name     string
-	Vehicles []publicTransport.PublicTransport
+	Name      string
+	Vehicles  []publicTransport.PublicTransport
+	Legs      []Leg
+	observers events.Observers
+}
+
+// Subscribe sends an event to sink every time a vehicle is added to the route.
+func (r *Route) Subscribe(sink events.Sink) {
+	r.observers.Subscribe(sink)
 }
 
 func (r *Route) AddVehicleToRoute(vehicle publicTransport.PublicTransport) {
-	r.Vehicles = append(r.Vehicles, vehicle)
+	r.AddLeg(Leg{Vehicle: vehicle})
+}
+
+func (r *Route) AddLeg(leg Leg) {
+	if leg.Route == "" {
+		leg.Route = r.Name
+	}
+	r.Legs = append(r.Legs, leg)
+	r.Vehicles = append(r.Vehicles, leg.Vehicle)
+
+	r.observers.Emit(events.Event{
+		Type:        events.VehicleAdded,
+		Route:       r.Name,
+		VehicleType: leg.Vehicle.GetType(),
+		Vehicle:     leg.Vehicle.GetName(),
+		OnBoard:     leg.Vehicle.Passengers(),
+	})
+}
+
+func (r *Route) IsScheduled() bool {
+	for _, leg := range r.Legs {
+		if leg.From == nil || leg.To == nil {
+			return false
+		}
+	}
+	return len(r.Legs) > 0
+}
+
+// TransferWindows returns the time between arriving with one leg and
+// departing with the next, for every change of vehicle on a scheduled route.
+func (r *Route) TransferWindows() []Transfer {
+	if !r.IsScheduled() {
+		return nil
+	}
+	var transfers []Transfer
+	for i := 1; i < len(r.Legs); i++ {
+		prev, next := r.Legs[i-1], r.Legs[i]
+		transfers = append(transfers, Transfer{
+			At:          next.From,
+			Arrival:     prev.Arrival,
+			Departure:   next.Departure,
+			Window:      next.Departure - prev.Arrival,
+			MinTransfer: next.From.MinTransfer,
+		})
+	}
+	return transfers
+}
+
+// Validate checks that every leg starts where the previous one ended and that
+// each transfer leaves at least the minimum transfer time of its stop.
+func (r *Route) Validate() error {
+	if !r.IsScheduled() {
+		return nil
+	}
+	for i, leg := range r.Legs {
+		if leg.Arrival < leg.Departure {
+			return fmt.Errorf("route %s: leg %d arrives before it departs", r.Name, i+1)
+		}
+		if i > 0 && r.Legs[i-1].To != leg.From {
+			return fmt.Errorf("route %s: leg %d starts at %s, but leg %d ends at %s",
+				r.Name, i+1, leg.From.Name, i, r.Legs[i-1].To.Name)
+		}
+	}
+	for _, t := range r.TransferWindows() {
+		if t.Window < t.MinTransfer {
+			return fmt.Errorf("route %s: transfer at %s is %s, minimum is %s",
+				r.Name, t.At.Name, FormatDuration(t.Window), FormatDuration(t.MinTransfer))
+		}
+	}
+	return nil
 }
 
 func (r *Route) ShowListOfVehiclesOnRoute() {
 	fmt.Printf("All vehicle in our route %s:\n", r.Name)
-	for i, vehicle := range r.Vehicles {
-		fmt.Printf("%d. %s: %s \n", i+1, vehicle.GetType(), vehicle.GetName())
+	if !r.IsScheduled() {
+		for i, vehicle := range r.Vehicles {
+			fmt.Printf("%d. %s: %s \n", i+1, vehicle.GetType(), vehicle.GetName())
+		}
+		return
+	}
+
+	transfers := r.TransferWindows()
+	for i, leg := range r.Legs {
+		fmt.Printf("%d. %s: %s  %s %s -> %s %s\n", i+1, leg.Vehicle.GetType(), leg.Vehicle.GetName(),
+			FormatClock(leg.Departure), leg.From.Name, FormatClock(leg.Arrival), leg.To.Name)
+		if i < len(transfers) {
+			t := transfers[i]
+			fmt.Printf("   transfer at %s: %s (min %s)\n", t.At.Name, FormatDuration(t.Window), FormatDuration(t.MinTransfer))
+		}
 	}
 }
@@ -0,0 +1,227 @@
+package simulation
+
+import (
+	"GoLangProjector/hw6/route"
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// Demand is how many passengers per hour come to each stop of a route,
+	// by route name. Routes not listed use DefaultDemand.
+	Demand        map[string]float64
+	DefaultDemand float64
+	// Window is how long before a departure passengers start to arrive.
+	Window time.Duration
+	Seed   int64
+}
+
+type RouteReport struct {
+	Route       string
+	Passengers  int
+	Boardings   int
+	Denied      int
+	Delivered   int
+	AverageWait time.Duration
+	AverageLoad float64
+	PeakLoad    float64
+}
+
+type Report struct {
+	Routes []RouteReport
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-10s %10s %9s %7s %9s %9s %8s %8s\n",
+		"route", "passengers", "boardings", "denied", "delivered", "avg wait", "avg load", "peak")
+	for _, rr := range r.Routes {
+		fmt.Fprintf(&sb, "%-10s %10d %9d %7d %9d %9s %7.0f%% %7.0f%%\n",
+			rr.Route, rr.Passengers, rr.Boardings, rr.Denied, rr.Delivered,
+			route.FormatDuration(rr.AverageWait), rr.AverageLoad*100, rr.PeakLoad*100)
+	}
+	return sb.String()
+}
+
+// Event order for things that happen at the same moment: passengers get off
+// first, so they can catch a connection leaving at that time.
+const (
+	orderArrival = iota
+	orderPassenger
+	orderDeparture
+)
+
+type event struct {
+	at    time.Duration
+	order int
+	seq   int
+	run   func()
+}
+
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	if q[i].order != q[j].order {
+		return q[i].order < q[j].order
+	}
+	return q[i].seq < q[j].seq
+}
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)   { *q = append(*q, x.(*event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+type passenger struct {
+	destination int
+	waitingFrom time.Duration
+}
+
+type routeState struct {
+	r         *route.Route
+	waiting   [][]*passenger
+	report    RouteReport
+	totalWait time.Duration
+	totalLoad float64
+	runs      int
+}
+
+type Simulator struct {
+	config Config
+	rnd    *rand.Rand
+	queue  eventQueue
+	seq    int
+	now    time.Duration
+	routes []*routeState
+}
+
+func New(routes []*route.Route, config Config) *Simulator {
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	s := &Simulator{
+		config: config,
+		rnd:    rand.New(rand.NewSource(config.Seed)),
+	}
+	for _, r := range routes {
+		if !r.IsScheduled() {
+			continue
+		}
+		s.routes = append(s.routes, &routeState{
+			r:       r,
+			waiting: make([][]*passenger, len(r.Legs)),
+			report:  RouteReport{Route: r.Name},
+		})
+	}
+	return s
+}
+
+// Run plays the whole timetable once and reports what happened on every route.
+// It boards and drops off passengers on the real vehicles, so vehicles shared
+// between routes share their seats here too.
+func (s *Simulator) Run() Report {
+	for _, rs := range s.routes {
+		s.scheduleRoute(rs)
+	}
+	for s.queue.Len() > 0 {
+		e := heap.Pop(&s.queue).(*event)
+		s.now = e.at
+		e.run()
+	}
+
+	var report Report
+	for _, rs := range s.routes {
+		if rs.report.Boardings > 0 {
+			rs.report.AverageWait = rs.totalWait / time.Duration(rs.report.Boardings)
+		}
+		if rs.runs > 0 {
+			rs.report.AverageLoad = rs.totalLoad / float64(rs.runs)
+		}
+		report.Routes = append(report.Routes, rs.report)
+	}
+	return report
+}
+
+func (s *Simulator) schedule(at time.Duration, order int, run func()) {
+	s.seq++
+	heap.Push(&s.queue, &event{at: at, order: order, seq: s.seq, run: run})
+}
+
+// scheduleRoute adds the departures of every leg and the passengers who come
+// to its first stop during the window before it leaves.
+func (s *Simulator) scheduleRoute(rs *routeState) {
+	perHour, ok := s.config.Demand[rs.r.Name]
+	if !ok {
+		perHour = s.config.DefaultDemand
+	}
+	for i, leg := range rs.r.Legs {
+		s.schedule(leg.Departure, orderDeparture, func() { s.depart(rs, i) })
+
+		if perHour <= 0 {
+			continue
+		}
+		at := leg.Departure - s.config.Window
+		for {
+			at += time.Duration(s.rnd.ExpFloat64() / perHour * float64(time.Hour))
+			if at > leg.Departure {
+				break
+			}
+			p := &passenger{destination: i + s.rnd.Intn(len(rs.r.Legs)-i), waitingFrom: at}
+			s.schedule(at, orderPassenger, func() {
+				rs.report.Passengers++
+				rs.waiting[i] = append(rs.waiting[i], p)
+			})
+		}
+	}
+}
+
+func (s *Simulator) depart(rs *routeState, i int) {
+	leg := rs.r.Legs[i]
+	waiting := rs.waiting[i]
+	rs.waiting[i] = nil
+
+	boarding := min(len(waiting), leg.Vehicle.FreeSeats())
+	if boarding > 0 && leg.Vehicle.AcceptPassenger(boarding) != nil {
+		boarding = 0
+	}
+	rs.report.Denied += len(waiting) - boarding
+	rs.report.Boardings += boarding
+	onBoard := waiting[:boarding]
+	for _, p := range onBoard {
+		rs.totalWait += s.now - p.waitingFrom
+	}
+
+	capacity := leg.Vehicle.Passengers() + leg.Vehicle.FreeSeats()
+	if capacity > 0 {
+		load := float64(leg.Vehicle.Passengers()) / float64(capacity)
+		rs.totalLoad += load
+		rs.report.PeakLoad = max(rs.report.PeakLoad, load)
+	}
+	rs.runs++
+
+	s.schedule(leg.Arrival, orderArrival, func() { s.arrive(rs, i, onBoard) })
+}
+
+func (s *Simulator) arrive(rs *routeState, i int, onBoard []*passenger) {
+	if len(onBoard) > 0 {
+		rs.r.Legs[i].Vehicle.DropOffPassenger(len(onBoard))
+	}
+	for _, p := range onBoard {
+		if p.destination == i {
+			rs.report.Delivered++
+			continue
+		}
+		p.waitingFrom = s.now
+		rs.waiting[i+1] = append(rs.waiting[i+1], p)
+	}
+}
@@ -0,0 +1,184 @@
+package ticket
+
+import (
+	"GoLangProjector/hw6/fare"
+	"GoLangProjector/hw6/publicTransport"
+	"GoLangProjector/hw6/route"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrTicketNotFound  = errors.New("ticket not found")
+	ErrTicketNotBooked = errors.New("ticket is not booked")
+	ErrEmptyRoute      = errors.New("route has no vehicles")
+)
+
+type Status string
+
+const (
+	StatusBooked    Status = "booked"
+	StatusUsed      Status = "used"
+	StatusCancelled Status = "cancelled"
+)
+
+type Ticket struct {
+	ID        int
+	Passenger string
+	Route     string
+	Vehicles  []string
+	Price     float64
+	Fare      fare.Breakdown
+	Status    Status
+	legs      []publicTransport.PublicTransport
+}
+
+type Office struct {
+	m       sync.Mutex
+	lastID  int
+	tickets map[int]*Ticket
+	fares   *fare.Engine
+}
+
+func NewOffice(fares *fare.Engine) *Office {
+	return &Office{
+		tickets: make(map[int]*Ticket),
+		fares:   fares,
+	}
+}
+
+// Buy reserves one seat on every vehicle of the route. Either every leg is
+// reserved or, if any vehicle is full, the seats taken so far are released
+// and no ticket is issued; the error then wraps publicTransport.ErrFull.
+// A route without vehicles has nothing to book and returns ErrEmptyRoute.
+func (o *Office) Buy(passenger string, category fare.Category, class fare.Class, r *route.Route) (Ticket, error) {
+	if len(r.Vehicles) == 0 {
+		return Ticket{}, fmt.Errorf("%w: %s", ErrEmptyRoute, r.Name)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	breakdown, err := o.fares.Calculate(r, category, class)
+	if err != nil {
+		return Ticket{}, err
+	}
+	for i, vehicle := range r.Vehicles {
+		if err := vehicle.AcceptPassenger(1); err != nil {
+			return Ticket{}, errors.Join(err, release(r.Vehicles[:i]))
+		}
+	}
+
+	o.lastID++
+	t := &Ticket{
+		ID:        o.lastID,
+		Passenger: passenger,
+		Route:     r.Name,
+		Price:     breakdown.Total,
+		Fare:      breakdown,
+		Status:    StatusBooked,
+		legs:      append([]publicTransport.PublicTransport(nil), r.Vehicles...),
+	}
+	for _, vehicle := range t.legs {
+		t.Vehicles = append(t.Vehicles, vehicle.GetType()+" "+vehicle.GetName())
+	}
+	o.tickets[t.ID] = t
+	return *t, nil
+}
+
+// Cancel releases the seats of a booked ticket and returns the refund.
+func (o *Office) Cancel(id int) (float64, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	t, err := o.booked(id)
+	if err != nil {
+		return 0, err
+	}
+	if err := release(t.legs); err != nil {
+		return 0, err
+	}
+	t.Status = StatusCancelled
+	return t.Price, nil
+}
+
+// Use marks the ticket as travelled; the passenger leaves every vehicle.
+func (o *Office) Use(id int) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	t, err := o.booked(id)
+	if err != nil {
+		return err
+	}
+	if err := release(t.legs); err != nil {
+		return err
+	}
+	t.Status = StatusUsed
+	return nil
+}
+
+func (o *Office) GetTicket(id int) (Ticket, bool) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	t, ok := o.tickets[id]
+	if !ok {
+		return Ticket{}, false
+	}
+	return *t, true
+}
+
+// Reserved counts the seats that booked tickets hold on the vehicle.
+func (o *Office) Reserved(vehicle publicTransport.PublicTransport) int {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	seats := 0
+	for _, t := range o.tickets {
+		if t.Status != StatusBooked {
+			continue
+		}
+		for _, leg := range t.legs {
+			if leg == vehicle {
+				seats++
+			}
+		}
+	}
+	return seats
+}
+
+func (o *Office) GetAllTickets() []Ticket {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	var tickets = make([]Ticket, 0, len(o.tickets))
+	for _, t := range o.tickets {
+		tickets = append(tickets, *t)
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ID < tickets[j].ID
+	})
+	return tickets
+}
+
+func (o *Office) booked(id int) (*Ticket, error) {
+	t, ok := o.tickets[id]
+	if !ok {
+		return nil, ErrTicketNotFound
+	}
+	if t.Status != StatusBooked {
+		return nil, fmt.Errorf("%w: ticket %d is %s", ErrTicketNotBooked, id, t.Status)
+	}
+	return t, nil
+}
+
+func release(vehicles []publicTransport.PublicTransport) error {
+	var errs []error
+	for _, vehicle := range vehicles {
+		errs = append(errs, vehicle.DropOffPassenger(1))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,3 @@
+module GoLangProjector/hw7
+
+go 1.22.3
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+const timeFormat = "15:04:05.000"
+
+func main() {
+	source := flag.String("source", "random", "where numbers come from: random, file or stdin")
+	filePath := flag.String("file", "", "file with numbers for -source file")
+	count := flag.Int("count", 10, "how many numbers to read (0 reads until the source ends)")
+	rate := flag.Duration("rate", 2*time.Second, "pause after every number")
+	minNum := flag.Int("min", 0, "smallest random number")
+	maxNum := flag.Int("max", 99, "largest random number")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for random numbers")
+	percentiles := flag.String("percentiles", "50,90,99", "comma separated percentiles to estimate")
+	ema := flag.Float64("ema", 0, "weight of the newest number in an exponential moving average (0 disables it)")
+	numbersBuffer := flag.Int("numbers-buffer", 1, "numbers waiting to be aggregated")
+	numbersOverflow := flag.String("numbers-overflow", "block", "when the numbers buffer is full: block, drop-oldest, drop-newest or sample")
+	snapshotsBuffer := flag.Int("snapshots-buffer", 1, "results waiting to be printed")
+	snapshotsOverflow := flag.String("snapshots-overflow", "block", "when the results buffer is full: block, drop-oldest, drop-newest or sample")
+	sampleEvery := flag.Int("sample-every", 10, "keep every Nth item while a buffer with the sample policy is full")
+	printDelay := flag.Duration("print-delay", 0, "pause after printing every result, to try out a slow terminal")
+	metricsEvery := flag.Duration("metrics", 0, "print buffer metrics to stderr this often and at the end (0 disables them)")
+	windowSpecs := flag.String("window", "", "comma separated windows: count:SIZE[:STEP] or time:SIZE[:STEP], e.g. count:5,time:10s:2s")
+	flag.Parse()
+
+	var src pipeline.Source
+	switch *source {
+	case "random":
+		if *maxNum < *minNum {
+			fmt.Println("-max must not be less than -min")
+			return
+		}
+		src = pipeline.NewRandomSource(*minNum, *maxNum, *seed)
+	case "file":
+		fileSource, err := pipeline.NewFileSource(*filePath)
+		if err != nil {
+			fmt.Println("Error open file:", err)
+			return
+		}
+		defer fileSource.Close()
+		src = fileSource
+	case "stdin":
+		src = pipeline.NewStdinSource()
+	default:
+		fmt.Println("Unknown source:", *source)
+		return
+	}
+
+	aggregators, err := newAggregators(*percentiles, *ema)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	windows, err := newWindows(*windowSpecs)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+
+	numbers, err := stageConfig(*numbersBuffer, *numbersOverflow, *sampleEvery)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	snapshots, err := stageConfig(*snapshotsBuffer, *snapshotsOverflow, *sampleEvery)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// The first Ctrl-C stops gracefully. Restoring the default handler right
+	// away lets a second Ctrl-C exit if shutting down hangs.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	p := pipeline.New(src, pipeline.Config{
+		Count:     *count,
+		Rate:      *rate,
+		Numbers:   numbers,
+		Snapshots: snapshots,
+	}, aggregators...)
+	p.AddWindows(windows...)
+
+	done := make(chan struct{})
+	if *metricsEvery > 0 {
+		go func() {
+			ticker := time.NewTicker(*metricsEvery)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					printMetrics(p.Metrics())
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	print := printAverage
+	if *printDelay > 0 {
+		print = func(snapshot pipeline.Snapshot) error {
+			err := printAverage(snapshot)
+			time.Sleep(*printDelay)
+			return err
+		}
+	}
+	summary, err := p.Run(ctx, print)
+	close(done)
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+	}
+	if ctx.Err() != nil {
+		fmt.Println("\nInterrupted")
+	}
+	printSummary(summary)
+	if *metricsEvery > 0 {
+		printMetrics(p.Metrics())
+	}
+}
+
+func stageConfig(buffer int, overflow string, sampleEvery int) (pipeline.StageConfig, error) {
+	policy, err := pipeline.ParseOverflow(overflow)
+	if err != nil {
+		return pipeline.StageConfig{}, err
+	}
+	return pipeline.StageConfig{Buffer: buffer, Overflow: policy, SampleEvery: sampleEvery}, nil
+}
+
+func newAggregators(percentiles string, ema float64) ([]pipeline.Aggregator, error) {
+	aggregators := []pipeline.Aggregator{&pipeline.Mean{}, &pipeline.Variance{}, &pipeline.MinMax{}}
+	if ema != 0 {
+		e, err := pipeline.NewEMA(ema)
+		if err != nil {
+			return nil, err
+		}
+		aggregators = append(aggregators, e)
+	}
+	if percentiles == "" {
+		return aggregators, nil
+	}
+	var values []float64
+	for _, field := range strings.Split(percentiles, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid percentile %q", field)
+		}
+		values = append(values, value)
+	}
+	p, err := pipeline.NewPercentiles(values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(aggregators, p), nil
+}
+
+// newWindows parses specs like count:5, count:5:1, time:10s or time:10s:2s.
+// Without a step the windows are tumbling.
+func newWindows(specs string) ([]pipeline.Windower, error) {
+	var windows []pipeline.Windower
+	if specs == "" {
+		return windows, nil
+	}
+	for _, spec := range strings.Split(specs, ",") {
+		parts := strings.Split(strings.TrimSpace(spec), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid window %q", spec)
+		}
+		step := parts[len(parts)-1]
+
+		var window pipeline.Windower
+		var err error
+		switch parts[0] {
+		case "count":
+			size, sizeErr := strconv.Atoi(parts[1])
+			every, stepErr := strconv.Atoi(step)
+			if sizeErr != nil || stepErr != nil {
+				return nil, fmt.Errorf("invalid window %q", spec)
+			}
+			window, err = pipeline.NewCountWindow(size, every)
+		case "time":
+			size, sizeErr := time.ParseDuration(parts[1])
+			every, stepErr := time.ParseDuration(step)
+			if sizeErr != nil || stepErr != nil {
+				return nil, fmt.Errorf("invalid window %q", spec)
+			}
+			window, err = pipeline.NewTimeWindow(size, every)
+		default:
+			return nil, fmt.Errorf("invalid window %q", spec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %w", spec, err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func printAverage(snapshot pipeline.Snapshot) error {
+	var b strings.Builder
+	if !snapshot.Final {
+		fmt.Fprintf(&b, "%d. Number: %g", snapshot.Count, snapshot.Num)
+		for _, value := range snapshot.Values {
+			fmt.Fprintf(&b, ", %s: %.2f", value.Name, value.Value)
+		}
+		b.WriteString("\n")
+	}
+	for _, w := range snapshot.Windows {
+		fmt.Fprintf(&b, "   Window %s: numbers %d-%d, %s - %s, count %d, mean %.2f, min %g, max %g\n",
+			w.Name, w.From, w.To, w.Start.Format(timeFormat), w.End.Format(timeFormat), w.Count, w.Mean, w.Min, w.Max)
+	}
+	_, err := fmt.Print(b.String())
+	return err
+}
+
+func printSummary(summary pipeline.Snapshot) {
+	fmt.Printf("Summary of %d numbers:\n", summary.Count)
+	for _, value := range summary.Values {
+		fmt.Printf("  %-10s %.2f\n", value.Name+":", value.Value)
+	}
+}
+
+func printMetrics(metrics []pipeline.Metrics) {
+	for _, m := range metrics {
+		fmt.Fprintf(os.Stderr, "[%s] queued %d, accepted %d, taken %d, dropped %d, %.1f/s\n",
+			m.Stage, m.Queued, m.Accepted, m.Taken, m.Dropped, m.Throughput)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+var ErrNoNumbers = errors.New("no numbers to find min and max")
+
+// printLimit is the largest amount of numbers that is printed.
+const printLimit = 50
+
+func main() {
+	a := flag.Int("min", 5, "smallest random number")
+	b := flag.Int("max", 300, "largest random number")
+	amount := flag.Int("amount", 10, "how many numbers to generate")
+	workers := flag.Int("workers", runtime.NumCPU(), "goroutines looking for min and max")
+	chunkSize := flag.Int("chunk", 10000, "numbers sent to a worker at a time")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for random numbers")
+	flag.Parse()
+	if *b < *a {
+		fmt.Println("-max must not be less than -min")
+		return
+	}
+
+	rnd := rand.New(rand.NewSource(*seed))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// The first Ctrl-C stops gracefully. Restoring the default handler right
+	// away lets a second Ctrl-C exit if shutting down hangs.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	chunks := make(chan []int)
+	var minMax pipeline.Partial[int]
+
+	// Like the pipeline of part 1, the stages get a context that only a
+	// failed stage cancels: Ctrl-C stops the generator, and the chunks it
+	// already sent are still counted.
+	g, stageCtx := pipeline.WithContext(context.WithoutCancel(ctx))
+	g.Go(func() error {
+		return generateNumbers(ctx, stageCtx, rnd, *a, *b, *amount, *chunkSize, chunks)
+	})
+	g.Go(func() error {
+		var err error
+		minMax, err = findMinMax(stageCtx, chunks, *workers)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("\nInterrupted, %d of %d numbers processed", minMax.Count, *amount)
+	}
+	fmt.Printf("\nMAX = %d, \nMIN = %d \nAVERAGE = %.2f \n", minMax.Max, minMax.Min, minMax.Mean())
+}
+
+func generate(rnd pipeline.Rand, a int, b int, amount int) []int {
+	numbers := make([]int, amount)
+	for i := range numbers {
+		numbers[i] = rnd.Intn(b-a+1) + a
+	}
+	return numbers
+}
+
+// generateNumbers generates and sends the numbers one chunk at a time, so
+// findMinMax starts on the first chunk while the rest are being generated.
+// Cancelling ctx or stageCtx stops it after the chunk it is sending; either
+// way it closes output, so the chunks sent so far are still processed.
+func generateNumbers(ctx context.Context, stageCtx context.Context, rnd pipeline.Rand, a int, b int, amount int,
+	chunkSize int, output chan<- []int) error {
+	defer close(output)
+	fmt.Printf("Generate %d numbers between [ %d, %d ] \n", amount, a, b)
+
+	if chunkSize <= 0 {
+		chunkSize = amount
+	}
+	for sent := 0; sent < amount; sent += chunkSize {
+		chunk := generate(rnd, a, b, min(chunkSize, amount-sent))
+		if amount <= printLimit {
+			for _, num := range chunk {
+				fmt.Printf("%d, ", num)
+			}
+		}
+		select {
+		case output <- chunk:
+		case <-ctx.Done():
+			return nil
+		case <-stageCtx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// findMinMax fans the chunks out to workers and merges their partial results.
+func findMinMax(ctx context.Context, input <-chan []int, workers int) (pipeline.Partial[int], error) {
+	minMax, err := pipeline.FanOut(ctx, input, workers)
+	if err != nil {
+		return minMax, err
+	}
+	if minMax.Count == 0 {
+		return minMax, ErrNoNumbers
+	}
+	return minMax, nil
+}
@@ -0,0 +1,225 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type Value struct {
+	Name  string
+	Value float64
+}
+
+// Aggregator updates its result with every number in O(1) time and memory.
+type Aggregator interface {
+	Add(num float64)
+	Values() []Value
+}
+
+type Mean struct {
+	n    int
+	mean float64
+}
+
+func (m *Mean) Add(num float64) {
+	m.n++
+	m.mean += (num - m.mean) / float64(m.n)
+}
+
+func (m *Mean) Values() []Value {
+	return []Value{{Name: "Average", Value: m.mean}}
+}
+
+// Variance uses Welford's algorithm, which stays accurate for long streams.
+type Variance struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+func (v *Variance) Add(num float64) {
+	v.n++
+	delta := num - v.mean
+	v.mean += delta / float64(v.n)
+	v.m2 += delta * (num - v.mean)
+}
+
+func (v *Variance) Values() []Value {
+	variance := 0.0
+	if v.n > 1 {
+		variance = v.m2 / float64(v.n-1)
+	}
+	return []Value{
+		{Name: "Variance", Value: variance},
+		{Name: "StdDev", Value: math.Sqrt(variance)},
+	}
+}
+
+// EMA is the exponential moving average. Alpha between 0 and 1 is the weight
+// of the newest number; the larger it is, the faster the average follows.
+type EMA struct {
+	alpha float64
+	n     int
+	ema   float64
+}
+
+func NewEMA(alpha float64) (*EMA, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("EMA alpha %v is not in (0, 1]", alpha)
+	}
+	return &EMA{alpha: alpha}, nil
+}
+
+func (e *EMA) Add(num float64) {
+	if e.n == 0 {
+		e.ema = num
+	} else {
+		e.ema += e.alpha * (num - e.ema)
+	}
+	e.n++
+}
+
+func (e *EMA) Values() []Value {
+	return []Value{{Name: "EMA", Value: e.ema}}
+}
+
+type MinMax struct {
+	n   int
+	min float64
+	max float64
+}
+
+func (m *MinMax) Add(num float64) {
+	if m.n == 0 || num < m.min {
+		m.min = num
+	}
+	if m.n == 0 || num > m.max {
+		m.max = num
+	}
+	m.n++
+}
+
+func (m *MinMax) Values() []Value {
+	return []Value{{Name: "Min", Value: m.min}, {Name: "Max", Value: m.max}}
+}
+
+// Percentiles estimates each quantile with the P² algorithm (Jain and
+// Chlamtac), keeping five markers per quantile instead of every number.
+type Percentiles struct {
+	estimators []*p2
+}
+
+// NewPercentiles takes percentiles between 0 and 100, e.g. 50, 90, 99.
+func NewPercentiles(percentiles ...float64) (*Percentiles, error) {
+	p := &Percentiles{}
+	for _, percentile := range percentiles {
+		if percentile <= 0 || percentile >= 100 {
+			return nil, fmt.Errorf("percentile %v is not between 0 and 100", percentile)
+		}
+		p.estimators = append(p.estimators, newP2(percentile/100))
+	}
+	return p, nil
+}
+
+func (p *Percentiles) Add(num float64) {
+	for _, e := range p.estimators {
+		e.add(num)
+	}
+}
+
+func (p *Percentiles) Values() []Value {
+	values := make([]Value, len(p.estimators))
+	for i, e := range p.estimators {
+		values[i] = Value{Name: fmt.Sprintf("P%g", e.q*100), Value: e.value()}
+	}
+	return values
+}
+
+type p2 struct {
+	q       float64
+	n       int
+	heights [5]float64
+	pos     [5]float64
+	desired [5]float64
+	inc     [5]float64
+}
+
+func newP2(q float64) *p2 {
+	return &p2{
+		q:       q,
+		pos:     [5]float64{1, 2, 3, 4, 5},
+		desired: [5]float64{1, 1 + 2*q, 1 + 4*q, 3 + 2*q, 5},
+		inc:     [5]float64{0, q / 2, q, (1 + q) / 2, 1},
+	}
+}
+
+func (e *p2) add(num float64) {
+	if e.n < 5 {
+		e.heights[e.n] = num
+		e.n++
+		if e.n == 5 {
+			sort.Float64s(e.heights[:])
+		}
+		return
+	}
+	e.n++
+
+	var k int
+	switch {
+	case num < e.heights[0]:
+		e.heights[0] = num
+		k = 0
+	case num >= e.heights[4]:
+		e.heights[4] = num
+		k = 3
+	default:
+		for k = 0; k < 3 && num >= e.heights[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		e.pos[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.inc[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.desired[i] - e.pos[i]
+		if (d >= 1 && e.pos[i+1]-e.pos[i] > 1) || (d <= -1 && e.pos[i-1]-e.pos[i] < -1) {
+			sign := math.Copysign(1, d)
+			h := e.parabolic(i, sign)
+			if e.heights[i-1] < h && h < e.heights[i+1] {
+				e.heights[i] = h
+			} else {
+				e.heights[i] = e.linear(i, sign)
+			}
+			e.pos[i] += sign
+		}
+	}
+}
+
+func (e *p2) parabolic(i int, d float64) float64 {
+	return e.heights[i] + d/(e.pos[i+1]-e.pos[i-1])*
+		((e.pos[i]-e.pos[i-1]+d)*(e.heights[i+1]-e.heights[i])/(e.pos[i+1]-e.pos[i])+
+			(e.pos[i+1]-e.pos[i]-d)*(e.heights[i]-e.heights[i-1])/(e.pos[i]-e.pos[i-1]))
+}
+
+func (e *p2) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.heights[i] + d*(e.heights[j]-e.heights[i])/(e.pos[j]-e.pos[i])
+}
+
+// value is exact while fewer than five numbers have been seen.
+func (e *p2) value() float64 {
+	if e.n >= 5 {
+		return e.heights[2]
+	}
+	if e.n == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), e.heights[:e.n]...)
+	sort.Float64s(sorted)
+	idx := int(math.Round(e.q * float64(e.n-1)))
+	return sorted[idx]
+}
@@ -0,0 +1,54 @@
+package pipeline_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+func TestPercentilesExactForFewNumbers(t *testing.T) {
+	p, err := pipeline.NewPercentiles(50, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range p.Values() {
+		if v.Value != 0 {
+			t.Errorf("%s of no numbers is %v, want 0", v.Name, v.Value)
+		}
+	}
+	for _, num := range []float64{30, 10, 20} {
+		p.Add(num)
+	}
+	want := []pipeline.Value{{Name: "P50", Value: 20}, {Name: "P90", Value: 30}}
+	if got := p.Values(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPercentilesEstimate(t *testing.T) {
+	p, err := pipeline.NewPercentiles(50, 90, 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A shuffled 1..10000: the true Pq is about q*100.
+	rnd := rand.New(rand.NewSource(1))
+	for _, i := range rnd.Perm(10000) {
+		p.Add(float64(i + 1))
+	}
+	want := map[string]float64{"P50": 5000, "P90": 9000, "P99": 9900}
+	for _, v := range p.Values() {
+		if math.Abs(v.Value-want[v.Name]) > 100 {
+			t.Errorf("%s is %v, want about %v", v.Name, v.Value, want[v.Name])
+		}
+	}
+}
+
+func TestNewPercentilesRange(t *testing.T) {
+	for _, percentile := range []float64{0, 100, -5, 150} {
+		if _, err := pipeline.NewPercentiles(percentile); err == nil {
+			t.Errorf("percentile %v accepted", percentile)
+		}
+	}
+}
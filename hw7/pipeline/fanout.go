@@ -0,0 +1,95 @@
+package pipeline
+
+import "context"
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Partial is the min, max and sum of a part of the numbers. Partials of
+// separate parts merge into the partial of all of them.
+type Partial[T Number] struct {
+	Min   T
+	Max   T
+	Sum   T
+	Count int
+}
+
+func PartialOf[T Number](numbers []T) Partial[T] {
+	var p Partial[T]
+	p.Min, p.Max, _ = MinMaxOf(numbers)
+	for _, num := range numbers {
+		p.Sum += num
+	}
+	p.Count = len(numbers)
+	return p
+}
+
+func (p Partial[T]) Merge(other Partial[T]) Partial[T] {
+	if other.Count == 0 {
+		return p
+	}
+	if p.Count == 0 {
+		return other
+	}
+	return Partial[T]{
+		Min:   min(p.Min, other.Min),
+		Max:   max(p.Max, other.Max),
+		Sum:   p.Sum + other.Sum,
+		Count: p.Count + other.Count,
+	}
+}
+
+func (p Partial[T]) Mean() float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	return float64(p.Sum) / float64(p.Count)
+}
+
+// Chunk splits numbers into parts of at most size numbers without copying.
+func Chunk[T any](numbers []T, size int) [][]T {
+	if size <= 0 {
+		size = len(numbers)
+	}
+	var chunks [][]T
+	for start := 0; start < len(numbers); start += size {
+		chunks = append(chunks, numbers[start:min(start+size, len(numbers))])
+	}
+	return chunks
+}
+
+// FanOut reads chunks with the given number of workers. Every worker merges
+// the partials of the chunks it read, and the partials of the workers are
+// merged into the result once the chunks channel is closed.
+func FanOut[T Number](ctx context.Context, chunks <-chan []T, workers int) (Partial[T], error) {
+	workers = max(workers, 1)
+	partials := make(chan Partial[T], workers)
+	g, ctx := WithContext(ctx)
+
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			var p Partial[T]
+			for {
+				select {
+				case chunk, ok := <-chunks:
+					if !ok {
+						partials <- p
+						return nil
+					}
+					p = p.Merge(PartialOf(chunk))
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		})
+	}
+
+	err := g.Wait()
+	close(partials)
+	var result Partial[T]
+	for p := range partials {
+		result = result.Merge(p)
+	}
+	return result, err
+}
@@ -0,0 +1,94 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"GoLangProjector/hw7/pipeline"
+	"GoLangProjector/hw7/pipeline/pipelinetest"
+)
+
+func TestFanOutMatchesOneGoroutine(t *testing.T) {
+	checkLeaks(t)
+	source := pipeline.NewRandomSourceWith(-50, 50, pipelinetest.NewInts(17, 3, 99, 41, 0, 62, 8))
+	numbers := make([]int, 1000)
+	for i := range numbers {
+		num, err := source.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers[i] = int(num)
+	}
+
+	chunks := make(chan []int)
+	go func() {
+		defer close(chunks)
+		for _, chunk := range pipeline.Chunk(numbers, 64) {
+			chunks <- chunk
+		}
+	}()
+	got, err := pipeline.FanOut(context.Background(), chunks, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := pipeline.PartialOf(numbers); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestFanOutCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.FanOut(ctx, make(chan []int), 4); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled fan-out returned %v", err)
+	}
+}
+
+const (
+	benchNumbers = 1_000_000
+	benchChunk   = 10_000
+)
+
+func benchmarkInput() []int {
+	rnd := rand.New(rand.NewSource(1))
+	numbers := make([]int, benchNumbers)
+	for i := range numbers {
+		numbers[i] = rnd.Intn(1000)
+	}
+	return numbers
+}
+
+// BenchmarkPartialOf scans every number in one goroutine, the baseline for
+// BenchmarkFanOut.
+func BenchmarkPartialOf(b *testing.B) {
+	numbers := benchmarkInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pipeline.PartialOf(numbers)
+	}
+}
+
+func BenchmarkFanOut(b *testing.B) {
+	chunks := pipeline.Chunk(benchmarkInput(), benchChunk)
+	for workers := 1; workers <= runtime.NumCPU(); workers *= 2 {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				input := make(chan []int)
+				go func() {
+					defer close(input)
+					for _, chunk := range chunks {
+						input <- chunk
+					}
+				}()
+				if _, err := pipeline.FanOut(context.Background(), input, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
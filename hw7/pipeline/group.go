@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs stages in goroutines like golang.org/x/sync/errgroup: the first
+// stage to fail cancels the context of the others and its error is returned
+// by Wait.
+type Group struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	cancel context.CancelFunc
+}
+
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+func (g *Group) Go(stage func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := stage(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until every stage returned and reports the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.err
+}
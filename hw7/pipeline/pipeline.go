@@ -0,0 +1,195 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	// Count is how many numbers to read. 0 reads until the source ends.
+	Count int
+	// Rate is the pause after every number. 0 reads as fast as possible.
+	Rate time.Duration
+	// Numbers buffers the numbers waiting to be aggregated.
+	Numbers StageConfig
+	// Snapshots buffers the snapshots waiting to be printed.
+	Snapshots StageConfig
+	// Clock defaults to SystemClock.
+	Clock Clock
+}
+
+// Snapshot is the state of every aggregator after Num was added, with the
+// windows that Num closed. The last snapshot of a stream may have Final set;
+// it repeats the last number and only carries the windows left open.
+type Snapshot struct {
+	Count   int
+	Num     float64
+	Time    time.Time
+	Values  []Value
+	Windows []Window
+	Final   bool
+}
+
+type Pipeline struct {
+	m           sync.Mutex
+	source      Source
+	config      Config
+	aggregators []Aggregator
+	windows     []Windower
+	numbers     *queue[reading]
+	snapshots   *queue[Snapshot]
+}
+
+func New(source Source, config Config, aggregators ...Aggregator) *Pipeline {
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	return &Pipeline{source: source, config: config, aggregators: aggregators}
+}
+
+// AddWindows adds windowed aggregations next to the all-time aggregators.
+func (p *Pipeline) AddWindows(windows ...Windower) {
+	p.windows = append(p.windows, windows...)
+}
+
+type reading struct {
+	num float64
+	at  time.Time
+}
+
+// Printer shows a snapshot. An error stops the whole pipeline.
+type Printer func(Snapshot) error
+
+// Run connects the generate, aggregate and print stages with queues and
+// waits until the source is exhausted and every snapshot was printed. It
+// returns the last aggregated snapshot, which summarizes the whole stream
+// even when the printer skipped some snapshots.
+//
+// Cancelling ctx is a graceful stop: no more numbers are read, but the ones
+// already in flight are still aggregated and printed. A source blocked in
+// Next, like stdin waiting for input, notices it only after Next returns.
+// An error in any stage stops the other stages at once and is returned.
+func (p *Pipeline) Run(ctx context.Context, print Printer) (Snapshot, error) {
+	numbers := newQueue[reading]("numbers", p.config.Numbers, p.config.Clock)
+	snapshots := newQueue[Snapshot]("snapshots", p.config.Snapshots, p.config.Clock)
+	p.m.Lock()
+	p.numbers, p.snapshots = numbers, snapshots
+	p.m.Unlock()
+	var last Snapshot
+
+	// The stages get a context that only a failed stage cancels, so that
+	// cancelling ctx lets them drain instead of dropping values.
+	g, stageCtx := WithContext(context.WithoutCancel(ctx))
+
+	g.Go(func() error {
+		defer numbers.close()
+		return p.generate(ctx, stageCtx, numbers)
+	})
+	g.Go(func() error {
+		defer snapshots.close()
+		var err error
+		last, err = p.aggregate(stageCtx, numbers, snapshots)
+		return err
+	})
+	g.Go(func() error {
+		for {
+			snapshot, ok := snapshots.get(stageCtx)
+			if !ok {
+				return nil
+			}
+			if err := print(snapshot); err != nil {
+				return err
+			}
+		}
+	})
+
+	err := g.Wait()
+	return last, err
+}
+
+// Metrics reports the queues in front of the aggregate and print stages. It
+// may be called while Run is working.
+func (p *Pipeline) Metrics() []Metrics {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.numbers == nil {
+		return nil
+	}
+	return []Metrics{p.numbers.metrics(), p.snapshots.metrics()}
+}
+
+func (p *Pipeline) generate(ctx context.Context, stageCtx context.Context, output *queue[reading]) error {
+	for i := 0; p.config.Count == 0 || i < p.config.Count; i++ {
+		if ctx.Err() != nil || stageCtx.Err() != nil {
+			return nil
+		}
+		num, err := p.source.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if output.put(stageCtx, reading{num: num, at: p.config.Clock.Now()}, false) != nil {
+			return nil
+		}
+		if p.config.Rate > 0 && !sleep(ctx, stageCtx, p.config.Clock, p.config.Rate) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *Pipeline) aggregate(ctx context.Context, input *queue[reading], output *queue[Snapshot]) (Snapshot, error) {
+	var last Snapshot
+	for {
+		r, ok := input.get(ctx)
+		if !ok {
+			break
+		}
+		snapshot := Snapshot{Count: last.Count + 1, Num: r.num, Time: r.at}
+		for _, aggregator := range p.aggregators {
+			aggregator.Add(r.num)
+			snapshot.Values = append(snapshot.Values, aggregator.Values()...)
+		}
+		for _, window := range p.windows {
+			snapshot.Windows = append(snapshot.Windows, window.Add(snapshot.Count, r.num, r.at)...)
+		}
+		// Snapshots that close windows are never dropped, or their windows
+		// would be lost.
+		if output.put(ctx, snapshot, len(snapshot.Windows) > 0) != nil {
+			return last, nil
+		}
+		last = snapshot
+	}
+	if ctx.Err() != nil {
+		return last, nil
+	}
+
+	final := last
+	final.Windows, final.Final = nil, true
+	for _, window := range p.windows {
+		final.Windows = append(final.Windows, window.Flush()...)
+	}
+	if len(final.Windows) > 0 {
+		output.put(ctx, final, true)
+	}
+	return last, nil
+}
+
+// sleep waits for d and reports false if either context was cancelled first.
+func sleep(ctx context.Context, stageCtx context.Context, clock Clock, d time.Duration) bool {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	case <-stageCtx.Done():
+		return false
+	}
+}
@@ -0,0 +1,281 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+	"GoLangProjector/hw7/pipeline/pipelinetest"
+)
+
+// timeout bounds every wait in real time, so that a broken test fails
+// instead of hanging.
+const timeout = 2 * time.Second
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// checkLeaks fails the test if goroutines started during it are still
+// running once it ends.
+func checkLeaks(t *testing.T) {
+	t.Helper()
+	goroutines := pipelinetest.WatchGoroutines()
+	t.Cleanup(func() {
+		if err := goroutines.Check(timeout); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// run starts the pipeline and returns a channel with its result.
+func run(ctx context.Context, p *pipeline.Pipeline, r *pipelinetest.Recorder) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Run(ctx, r.Print)
+		done <- err
+	}()
+	return done
+}
+
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		t.Fatal("pipeline did not stop")
+		return nil
+	}
+}
+
+// eventually polls cond until it holds or timeout passes.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func value(snapshot pipeline.Snapshot, name string) float64 {
+	for _, v := range snapshot.Values {
+		if v.Name == name {
+			return v.Value
+		}
+	}
+	return 0
+}
+
+func TestRunWithFakeClock(t *testing.T) {
+	checkLeaks(t)
+	clock := pipelinetest.NewFakeClock(start)
+	window, err := pipeline.NewTimeWindow(2*time.Second, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pipeline.New(pipelinetest.NewNumbers(1, 2, 3), pipeline.Config{
+		Count: 3,
+		Rate:  time.Second,
+		Clock: clock,
+	}, &pipeline.Mean{})
+	p.AddWindows(window)
+	r := &pipelinetest.Recorder{}
+	done := run(context.Background(), p, r)
+
+	for i := 0; i < 3; i++ {
+		if !clock.WaitForTimers(1, timeout) {
+			t.Fatalf("no timer after number %d", i+1)
+		}
+		clock.Advance(time.Second)
+	}
+	if err := wait(t, done); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := r.Snapshots()
+	if len(snapshots) != 4 {
+		t.Fatalf("got %d snapshots, want 3 and a final one", len(snapshots))
+	}
+	for i, mean := range []float64{1, 1.5, 2} {
+		if !snapshots[i].Time.Equal(start.Add(time.Duration(i) * time.Second)) {
+			t.Errorf("number %d read at %v", i+1, snapshots[i].Time)
+		}
+		if got := value(snapshots[i], "Average"); got != mean {
+			t.Errorf("average after number %d is %v, want %v", i+1, got, mean)
+		}
+	}
+	closed := snapshots[2].Windows
+	if len(closed) != 1 || closed[0].From != 1 || closed[0].To != 2 || !closed[0].End.Equal(start.Add(2*time.Second)) {
+		t.Errorf("number 3 closed windows %+v, want numbers 1-2", closed)
+	}
+	flushed := snapshots[3].Windows
+	if !snapshots[3].Final || len(flushed) != 1 || flushed[0].From != 3 || flushed[0].Mean != 3 {
+		t.Errorf("final windows %+v, want number 3", flushed)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	checkLeaks(t)
+	clock := pipelinetest.NewFakeClock(start)
+	source := pipeline.NewRandomSourceWith(0, 99, pipelinetest.NewInts(5))
+	p := pipeline.New(source, pipeline.Config{Rate: time.Hour, Clock: clock}, &pipeline.Mean{})
+	r := &pipelinetest.Recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := run(ctx, p, r)
+
+	if !clock.WaitForTimers(1, timeout) {
+		t.Fatal("generator is not waiting")
+	}
+	cancel()
+	if err := wait(t, done); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(r.Snapshots()); got != 1 {
+		t.Fatalf("printed %d snapshots, want the 1 in flight", got)
+	}
+}
+
+func TestRunSourceError(t *testing.T) {
+	checkLeaks(t)
+	errBroken := errors.New("broken source")
+	source := pipelinetest.NewNumbers(1, 2)
+	source.Err = errBroken
+	p := pipeline.New(source, pipeline.Config{}, &pipeline.Mean{})
+	r := &pipelinetest.Recorder{}
+	if err := wait(t, run(context.Background(), p, r)); !errors.Is(err, errBroken) {
+		t.Fatalf("got error %v, want %v", err, errBroken)
+	}
+}
+
+func TestRunPrinterError(t *testing.T) {
+	checkLeaks(t)
+	errBroken := errors.New("broken printer")
+	source := pipeline.NewRandomSourceWith(0, 99, pipelinetest.NewInts(1, 2, 3))
+	p := pipeline.New(source, pipeline.Config{}, &pipeline.Mean{})
+	r := &pipelinetest.Recorder{Err: errBroken}
+	if err := wait(t, run(context.Background(), p, r)); !errors.Is(err, errBroken) {
+		t.Fatalf("got error %v, want %v", err, errBroken)
+	}
+}
+
+func TestRunDropNewestCountsEveryNumber(t *testing.T) {
+	checkLeaks(t)
+	const count = 20
+	p := pipeline.New(pipeline.NewRandomSourceWith(0, 99, pipelinetest.NewInts(4, 8)), pipeline.Config{
+		Count:     count,
+		Snapshots: pipeline.StageConfig{Buffer: 2, Overflow: pipeline.DropNewest},
+	}, &pipeline.Mean{})
+	r := &pipelinetest.Recorder{Block: make(chan struct{})}
+	done := run(context.Background(), p, r)
+
+	// Let the printer take one snapshot at a time until the pipeline ends.
+	deadline := time.After(timeout)
+loop:
+	for {
+		select {
+		case r.Block <- struct{}{}:
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			break loop
+		case <-deadline:
+			t.Fatal("pipeline did not stop")
+		}
+	}
+
+	metrics := p.Metrics()[1]
+	printed := len(r.Snapshots())
+	if int64(printed) != metrics.Taken || metrics.Taken+metrics.Dropped != count || metrics.Queued != 0 {
+		t.Fatalf("printed %d, metrics %+v, want every one of %d printed or dropped", printed, metrics, count)
+	}
+}
+
+// TestRunOverflow holds the printer on the first snapshot while the other
+// 19 arrive at a buffer of one, so every policy sees the same 18 overflows.
+func TestRunOverflow(t *testing.T) {
+	const count = 20
+	tests := []struct {
+		name         string
+		stage        pipeline.StageConfig
+		wantPrinted  []int
+		wantAccepted int64
+		wantDropped  int64
+	}{
+		{
+			name:         "drop-newest keeps the first",
+			stage:        pipeline.StageConfig{Overflow: pipeline.DropNewest},
+			wantPrinted:  []int{1, 2},
+			wantAccepted: 2,
+			wantDropped:  18,
+		},
+		{
+			name:         "drop-oldest keeps the last",
+			stage:        pipeline.StageConfig{Overflow: pipeline.DropOldest},
+			wantPrinted:  []int{1, 20},
+			wantAccepted: 20,
+			wantDropped:  18,
+		},
+		{
+			// Overflows 4, 8, 12 and 16 replace the buffered snapshot; the
+			// 16th overflow is snapshot 18.
+			name:         "sample keeps every 4th overflow",
+			stage:        pipeline.StageConfig{Overflow: pipeline.Sample, SampleEvery: 4},
+			wantPrinted:  []int{1, 18},
+			wantAccepted: 6,
+			wantDropped:  18,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkLeaks(t)
+			clock := pipelinetest.NewFakeClock(start)
+			source := pipeline.NewRandomSourceWith(0, 99, pipelinetest.NewInts(4, 8, 15))
+			p := pipeline.New(source, pipeline.Config{
+				Count:     count,
+				Rate:      time.Second,
+				Clock:     clock,
+				Snapshots: tt.stage,
+			}, &pipeline.Mean{})
+			r := &pipelinetest.Recorder{Block: make(chan struct{})}
+			done := run(context.Background(), p, r)
+
+			for i := 0; i < count; i++ {
+				if !clock.WaitForTimers(1, timeout) {
+					t.Fatalf("no timer after number %d", i+1)
+				}
+				if i == 0 {
+					eventually(t, "the printer takes snapshot 1", func() bool {
+						return p.Metrics()[1].Taken == 1
+					})
+				}
+				clock.Advance(time.Second)
+			}
+			eventually(t, "every snapshot is buffered or dropped", func() bool {
+				metrics := p.Metrics()[1]
+				return metrics.Accepted == tt.wantAccepted && metrics.Dropped == tt.wantDropped
+			})
+			close(r.Block)
+			if err := wait(t, done); err != nil {
+				t.Fatal(err)
+			}
+
+			var printed []int
+			for _, snapshot := range r.Snapshots() {
+				printed = append(printed, snapshot.Count)
+			}
+			if !slices.Equal(printed, tt.wantPrinted) {
+				t.Errorf("printed snapshots %v, want %v", printed, tt.wantPrinted)
+			}
+			if metrics := p.Metrics()[1]; metrics.Taken != int64(len(tt.wantPrinted)) || metrics.Queued != 0 {
+				t.Errorf("metrics %+v, want %d taken and none queued", metrics, len(tt.wantPrinted))
+			}
+		})
+	}
+}
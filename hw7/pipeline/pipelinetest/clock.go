@@ -0,0 +1,108 @@
+// Package pipelinetest has helpers to run the pipeline deterministically:
+// a fake clock, fixed sources, a recording printer and a goroutine leak check.
+package pipelinetest
+
+import (
+	"sync"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+// FakeClock only moves when Advance is called. Timers fire once the clock
+// reaches their time.
+type FakeClock struct {
+	m       sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start, changed: make(chan struct{})}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) pipeline.Timer {
+	c.m.Lock()
+	defer c.m.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.notify()
+	return t
+}
+
+// Advance moves the clock forward and fires every timer that is due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.now = c.now.Add(d)
+	waiting := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			waiting = append(waiting, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = waiting
+	c.notify()
+}
+
+// WaitForTimers blocks until at least n timers wait for the clock, so that the
+// next Advance is sure to wake them. It reports false after timeout of real
+// time.
+func (c *FakeClock) WaitForTimers(n int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		c.m.Lock()
+		waiting, changed := len(c.timers), c.changed
+		c.m.Unlock()
+		if waiting >= n {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// notify wakes WaitForTimers. c.m must be held.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.m.Lock()
+	defer t.clock.m.Unlock()
+	for i, waiting := range t.clock.timers {
+		if waiting == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			t.clock.notify()
+			return true
+		}
+	}
+	return false
+}
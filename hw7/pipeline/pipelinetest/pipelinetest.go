@@ -0,0 +1,112 @@
+package pipelinetest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+// Numbers is a source of fixed numbers. After them it returns Err, or io.EOF
+// when Err is nil.
+type Numbers struct {
+	m    sync.Mutex
+	nums []float64
+	Err  error
+}
+
+func NewNumbers(nums ...float64) *Numbers {
+	return &Numbers{nums: nums}
+}
+
+func (n *Numbers) Next() (float64, error) {
+	n.m.Lock()
+	defer n.m.Unlock()
+	if len(n.nums) == 0 {
+		if n.Err != nil {
+			return 0, n.Err
+		}
+		return 0, io.EOF
+	}
+	num := n.nums[0]
+	n.nums = n.nums[1:]
+	return num, nil
+}
+
+// Ints gives Intn the fixed numbers in turn, starting again after the last.
+// It replaces *rand.Rand in a RandomSource.
+type Ints struct {
+	m    sync.Mutex
+	ints []int
+	next int
+}
+
+func NewInts(ints ...int) *Ints {
+	return &Ints{ints: ints}
+}
+
+func (r *Ints) Intn(n int) int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	i := r.ints[r.next%len(r.ints)]
+	r.next++
+	return i % n
+}
+
+// Recorder is a printer that keeps every snapshot. Block, when set, is
+// received from before every snapshot is recorded, to make a slow printer.
+type Recorder struct {
+	m         sync.Mutex
+	snapshots []pipeline.Snapshot
+	Block     chan struct{}
+	Err       error
+}
+
+func (r *Recorder) Print(snapshot pipeline.Snapshot) error {
+	if r.Block != nil {
+		<-r.Block
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.snapshots = append(r.snapshots, snapshot)
+	return r.Err
+}
+
+func (r *Recorder) Snapshots() []pipeline.Snapshot {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]pipeline.Snapshot(nil), r.snapshots...)
+}
+
+var ErrLeak = errors.New("goroutines leaked")
+
+// Goroutines remembers how many goroutines were running when it was made.
+type Goroutines struct {
+	before int
+}
+
+func WatchGoroutines() Goroutines {
+	return Goroutines{before: runtime.NumGoroutine()}
+}
+
+// Check waits up to timeout for the goroutines started since WatchGoroutines
+// to finish. If some are still running it returns ErrLeak with their stacks.
+func (g Goroutines) Check(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		running := runtime.NumGoroutine()
+		if running <= g.before {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			stacks := make([]byte, 1<<16)
+			stacks = stacks[:runtime.Stack(stacks, true)]
+			return fmt.Errorf("%w: %d running, %d before\n%s", ErrLeak, running, g.before, stacks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,195 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Overflow decides what a stage does with a new item when its buffer is full.
+type Overflow int
+
+const (
+	// Block waits until the next stage takes an item.
+	Block Overflow = iota
+	// DropOldest removes the oldest buffered item to make room.
+	DropOldest
+	// DropNewest drops the new item.
+	DropNewest
+	// Sample keeps only every SampleEvery-th new item, in place of the oldest
+	// buffered one, and drops the rest until the buffer has room again.
+	Sample
+)
+
+var overflowNames = map[string]Overflow{
+	"block":       Block,
+	"drop-oldest": DropOldest,
+	"drop-newest": DropNewest,
+	"sample":      Sample,
+}
+
+func ParseOverflow(name string) (Overflow, error) {
+	overflow, ok := overflowNames[name]
+	if !ok {
+		return Block, fmt.Errorf("unknown overflow policy %q", name)
+	}
+	return overflow, nil
+}
+
+func (o Overflow) String() string {
+	for name, overflow := range overflowNames {
+		if overflow == o {
+			return name
+		}
+	}
+	return fmt.Sprintf("Overflow(%d)", int(o))
+}
+
+type StageConfig struct {
+	// Buffer is how many items wait for the next stage. Less than 1 means 1.
+	Buffer      int
+	Overflow    Overflow
+	SampleEvery int
+}
+
+type Metrics struct {
+	Stage    string
+	Queued   int
+	Accepted int64
+	Taken    int64
+	// Dropped counts new items that were refused as well as accepted ones
+	// that were removed to make room.
+	Dropped int64
+	// Throughput is the number of taken items per second since the first item.
+	Throughput float64
+}
+
+// queue connects two stages like a buffered channel, but applies the overflow
+// policy of its stage and counts what happens to the items.
+type queue[T any] struct {
+	m        sync.Mutex
+	name     string
+	config   StageConfig
+	items    []T
+	closed   bool
+	changed  chan struct{}
+	clock    Clock
+	started  time.Time
+	accepted int64
+	taken    int64
+	dropped  int64
+	overflow int64
+}
+
+func newQueue[T any](name string, config StageConfig, clock Clock) *queue[T] {
+	config.Buffer = max(config.Buffer, 1)
+	config.SampleEvery = max(config.SampleEvery, 1)
+	return &queue[T]{name: name, config: config, clock: clock, changed: make(chan struct{})}
+}
+
+// notify wakes everyone waiting for a change. q.m must be held.
+func (q *queue[T]) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// put adds item following the overflow policy. With force it waits for room
+// whatever the policy, for items that must not be lost.
+func (q *queue[T]) put(ctx context.Context, item T, force bool) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+	if q.started.IsZero() {
+		q.started = q.clock.Now()
+	}
+
+	for len(q.items) >= q.config.Buffer {
+		policy := q.config.Overflow
+		if force {
+			policy = Block
+		}
+		switch policy {
+		case DropOldest:
+			q.items = q.items[1:]
+			q.dropped++
+		case DropNewest:
+			q.dropped++
+			return nil
+		case Sample:
+			q.overflow++
+			if q.overflow%int64(q.config.SampleEvery) != 0 {
+				q.dropped++
+				return nil
+			}
+			q.items = q.items[1:]
+			q.dropped++
+		default:
+			changed := q.changed
+			q.m.Unlock()
+			select {
+			case <-changed:
+				q.m.Lock()
+			case <-ctx.Done():
+				q.m.Lock()
+				return ctx.Err()
+			}
+		}
+	}
+
+	q.items = append(q.items, item)
+	q.accepted++
+	q.notify()
+	return nil
+}
+
+// get returns the oldest item and false once the queue is closed and empty or
+// ctx is cancelled.
+func (q *queue[T]) get(ctx context.Context) (T, bool) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	for len(q.items) == 0 {
+		if q.closed {
+			var zero T
+			return zero, false
+		}
+		changed := q.changed
+		q.m.Unlock()
+		select {
+		case <-changed:
+			q.m.Lock()
+		case <-ctx.Done():
+			q.m.Lock()
+			var zero T
+			return zero, false
+		}
+	}
+
+	item := q.items[0]
+	q.items = q.items[1:]
+	q.taken++
+	q.notify()
+	return item, true
+}
+
+func (q *queue[T]) close() {
+	q.m.Lock()
+	defer q.m.Unlock()
+	q.closed = true
+	q.notify()
+}
+
+func (q *queue[T]) metrics() Metrics {
+	q.m.Lock()
+	defer q.m.Unlock()
+	metrics := Metrics{
+		Stage:    q.name,
+		Queued:   len(q.items),
+		Accepted: q.accepted,
+		Taken:    q.taken,
+		Dropped:  q.dropped,
+	}
+	if elapsed := q.clock.Now().Sub(q.started); !q.started.IsZero() && elapsed > 0 {
+		metrics.Throughput = float64(q.taken) / elapsed.Seconds()
+	}
+	return metrics
+}
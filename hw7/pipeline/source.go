@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+)
+
+// Source produces the numbers of the stream. Next returns io.EOF once there
+// are no more numbers.
+type Source interface {
+	Next() (float64, error)
+}
+
+// Rand is the part of *rand.Rand that RandomSource needs, so that tests can
+// give it fixed numbers.
+type Rand interface {
+	Intn(n int) int
+}
+
+// RandomSource returns whole numbers in [Min, Max].
+type RandomSource struct {
+	Min int
+	Max int
+	rnd Rand
+}
+
+func NewRandomSource(min int, max int, seed int64) *RandomSource {
+	return NewRandomSourceWith(min, max, rand.New(rand.NewSource(seed)))
+}
+
+func NewRandomSourceWith(min int, max int, rnd Rand) *RandomSource {
+	return &RandomSource{Min: min, Max: max, rnd: rnd}
+}
+
+func (s *RandomSource) Next() (float64, error) {
+	return float64(s.rnd.Intn(s.Max-s.Min+1) + s.Min), nil
+}
+
+// ReaderSource reads numbers separated by spaces or new lines.
+type ReaderSource struct {
+	scanner *bufio.Scanner
+	closer  io.Closer
+}
+
+func NewReaderSource(r io.Reader) *ReaderSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return &ReaderSource{scanner: scanner}
+}
+
+func NewStdinSource() *ReaderSource {
+	return NewReaderSource(os.Stdin)
+}
+
+func NewFileSource(path string) (*ReaderSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	s := NewReaderSource(file)
+	s.closer = file
+	return s, nil
+}
+
+func (s *ReaderSource) Next() (float64, error) {
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+	num, err := strconv.ParseFloat(s.scanner.Text(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s.scanner.Text())
+	}
+	return num, nil
+}
+
+func (s *ReaderSource) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,21 @@
+package pipeline_test
+
+import (
+	"testing"
+
+	"GoLangProjector/hw7/pipeline"
+	"GoLangProjector/hw7/pipeline/pipelinetest"
+)
+
+func TestRandomSource(t *testing.T) {
+	source := pipeline.NewRandomSourceWith(10, 19, pipelinetest.NewInts(3, 7, 12))
+	for _, want := range []float64{13, 17, 12, 13} {
+		got, err := source.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidWindow = errors.New("window step must be positive and not larger than its size")
+
+// Window is the result of one closed window. From and To are the positions of
+// its first and last number in the stream, Start and End its time bounds.
+type Window struct {
+	Name  string
+	From  int
+	To    int
+	Start time.Time
+	End   time.Time
+	Count int
+	Mean  float64
+	Min   float64
+	Max   float64
+}
+
+// Windower groups the stream into windows and returns the ones that the
+// number at position pos, read at time at, closed. Flush returns the window
+// that is still open when the stream ends, if it has numbers.
+type Windower interface {
+	Add(pos int, num float64, at time.Time) []Window
+	Flush() []Window
+}
+
+type sample struct {
+	pos int
+	num float64
+	at  time.Time
+}
+
+// MinMaxOf returns the smallest and the largest number and false when there
+// are no numbers.
+func MinMaxOf[T cmp.Ordered](numbers []T) (T, T, bool) {
+	if len(numbers) == 0 {
+		var zero T
+		return zero, zero, false
+	}
+	minVal, maxVal := numbers[0], numbers[0]
+
+	for _, num := range numbers {
+		if num < minVal {
+			minVal = num
+		}
+		if num > maxVal {
+			maxVal = num
+		}
+	}
+	return minVal, maxVal, true
+}
+
+func newWindow(name string, samples []sample, start time.Time, end time.Time) Window {
+	numbers := make([]float64, len(samples))
+	total := 0.0
+	for i, s := range samples {
+		numbers[i] = s.num
+		total += s.num
+	}
+	w := Window{Name: name, Start: start, End: end, Count: len(samples)}
+	if len(samples) > 0 {
+		w.From, w.To = samples[0].pos, samples[len(samples)-1].pos
+		w.Mean = total / float64(len(samples))
+		w.Min, w.Max, _ = MinMaxOf(numbers)
+	}
+	return w
+}
+
+// CountWindow closes a window every Step numbers over the last Size numbers.
+// With Step equal to Size the windows are tumbling, with a smaller Step they
+// are sliding and overlap.
+type CountWindow struct {
+	size    int
+	step    int
+	samples []sample
+	pending int
+}
+
+func NewCountWindow(size int, step int) (*CountWindow, error) {
+	if size <= 0 || step <= 0 || step > size {
+		return nil, ErrInvalidWindow
+	}
+	return &CountWindow{size: size, step: step}, nil
+}
+
+func (w *CountWindow) name() string {
+	if w.step == w.size {
+		return fmt.Sprintf("tumbling %d", w.size)
+	}
+	return fmt.Sprintf("sliding %d/%d", w.size, w.step)
+}
+
+func (w *CountWindow) Add(pos int, num float64, at time.Time) []Window {
+	w.samples = append(w.samples, sample{pos: pos, num: num, at: at})
+	if len(w.samples) > w.size {
+		w.samples = w.samples[1:]
+	}
+	w.pending++
+	if len(w.samples) < w.size || w.pending < w.step {
+		return nil
+	}
+	w.pending = 0
+	return []Window{w.window(w.samples)}
+}
+
+func (w *CountWindow) Flush() []Window {
+	if w.pending == 0 || len(w.samples) == 0 {
+		return nil
+	}
+	// The open window holds the numbers it shares with the last closed
+	// window and the ones read since.
+	open := min(len(w.samples), w.size-w.step+w.pending)
+	w.pending = 0
+	return []Window{w.window(w.samples[len(w.samples)-open:])}
+}
+
+func (w *CountWindow) window(samples []sample) Window {
+	return newWindow(w.name(), samples, samples[0].at, samples[len(samples)-1].at)
+}
+
+// TimeWindow closes windows of length Size that start every Step, counted
+// from the time of the first number. A window is closed by the first number
+// read after its end, so a quiet stream closes it late but never early.
+type TimeWindow struct {
+	size    time.Duration
+	step    time.Duration
+	start   time.Time
+	samples []sample
+}
+
+func NewTimeWindow(size time.Duration, step time.Duration) (*TimeWindow, error) {
+	if size <= 0 || step <= 0 || step > size {
+		return nil, ErrInvalidWindow
+	}
+	return &TimeWindow{size: size, step: step}, nil
+}
+
+func (w *TimeWindow) name() string {
+	if w.step == w.size {
+		return fmt.Sprintf("tumbling %s", w.size)
+	}
+	return fmt.Sprintf("sliding %s/%s", w.size, w.step)
+}
+
+func (w *TimeWindow) Add(pos int, num float64, at time.Time) []Window {
+	if w.start.IsZero() {
+		w.start = at
+	}
+	var windows []Window
+	for !at.Before(w.start.Add(w.size)) {
+		end := w.start.Add(w.size)
+		if inside := w.between(w.start, end); len(inside) > 0 {
+			windows = append(windows, newWindow(w.name(), inside, w.start, end))
+		}
+		w.start = w.start.Add(w.step)
+		w.samples = w.between(w.start, at)
+	}
+	w.samples = append(w.samples, sample{pos: pos, num: num, at: at})
+	return windows
+}
+
+func (w *TimeWindow) Flush() []Window {
+	if len(w.samples) == 0 {
+		return nil
+	}
+	window := newWindow(w.name(), w.samples, w.start, w.start.Add(w.size))
+	w.samples = nil
+	return []Window{window}
+}
+
+// between returns the samples read in [start, end).
+func (w *TimeWindow) between(start time.Time, end time.Time) []sample {
+	first := len(w.samples)
+	for i, s := range w.samples {
+		if !s.at.Before(start) {
+			first = i
+			break
+		}
+	}
+	last := first
+	for last < len(w.samples) && w.samples[last].at.Before(end) {
+		last++
+	}
+	return w.samples[first:last]
+}
@@ -0,0 +1,104 @@
+package pipeline_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"GoLangProjector/hw7/pipeline"
+)
+
+// span is the positions of the numbers in a window.
+type span struct{ from, to int }
+
+func spans(windows []pipeline.Window) []span {
+	var got []span
+	for _, w := range windows {
+		got = append(got, span{w.From, w.To})
+	}
+	return got
+}
+
+func TestCountWindowFlush(t *testing.T) {
+	tests := []struct {
+		name       string
+		size, step int
+		numbers    int
+		wantClosed []span
+		wantFlush  []span
+	}{
+		{"tumbling flushes the rest", 3, 3, 7, []span{{1, 3}, {4, 6}}, []span{{7, 7}}},
+		{"tumbling without a rest", 3, 3, 6, []span{{1, 3}, {4, 6}}, nil},
+		{"sliding keeps the overlap", 4, 2, 5, []span{{1, 4}}, []span{{3, 5}}},
+		{"shorter than one window", 4, 2, 2, nil, []span{{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := pipeline.NewCountWindow(tt.size, tt.step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var closed []pipeline.Window
+			for pos := 1; pos <= tt.numbers; pos++ {
+				closed = append(closed, w.Add(pos, float64(pos), start.Add(time.Duration(pos)*time.Second))...)
+			}
+			if got := spans(closed); !slices.Equal(got, tt.wantClosed) {
+				t.Errorf("closed %v, want %v", got, tt.wantClosed)
+			}
+			flushed := w.Flush()
+			if got := spans(flushed); !slices.Equal(got, tt.wantFlush) {
+				t.Errorf("flushed %v, want %v", got, tt.wantFlush)
+			}
+			if len(flushed) == 1 {
+				from, to := tt.wantFlush[0].from, tt.wantFlush[0].to
+				if want := float64(from+to) / 2; flushed[0].Mean != want || flushed[0].Count != to-from+1 {
+					t.Errorf("flushed window %+v, want mean %v of %d numbers", flushed[0], want, to-from+1)
+				}
+			}
+			if again := w.Flush(); again != nil {
+				t.Errorf("second flush returned %v", spans(again))
+			}
+		})
+	}
+}
+
+func TestTimeWindowFlush(t *testing.T) {
+	w, err := pipeline.NewTimeWindow(2*time.Second, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flushed := w.Flush(); flushed != nil {
+		t.Fatalf("empty window flushed %v", spans(flushed))
+	}
+
+	var closed []pipeline.Window
+	for pos := 1; pos <= 3; pos++ {
+		closed = append(closed, w.Add(pos, float64(pos), start.Add(time.Duration(pos-1)*time.Second))...)
+	}
+	if got := spans(closed); !slices.Equal(got, []span{{1, 2}}) {
+		t.Fatalf("closed %v, want numbers 1-2", got)
+	}
+
+	flushed := w.Flush()
+	if len(flushed) != 1 {
+		t.Fatalf("flushed %d windows, want 1", len(flushed))
+	}
+	f := flushed[0]
+	if f.From != 3 || f.To != 3 || f.Mean != 3 ||
+		!f.Start.Equal(start.Add(2*time.Second)) || !f.End.Equal(start.Add(4*time.Second)) {
+		t.Errorf("flushed window %+v, want number 3 in [12:00:02, 12:00:04)", f)
+	}
+	if again := w.Flush(); again != nil {
+		t.Errorf("second flush returned %v", spans(again))
+	}
+}
+
+func TestNewWindowValidates(t *testing.T) {
+	if _, err := pipeline.NewCountWindow(2, 3); !errors.Is(err, pipeline.ErrInvalidWindow) {
+		t.Errorf("count window with step over size: %v", err)
+	}
+	if _, err := pipeline.NewTimeWindow(time.Second, 0); !errors.Is(err, pipeline.ErrInvalidWindow) {
+		t.Errorf("time window with zero step: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package auth issues and checks the bearer tokens of teachers. Tokens are
+// JWTs signed with HMAC-SHA256.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// header is the same for every token, so it is encoded once.
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokens signs tokens with secret. They are valid for ttl after Issue.
+func NewTokens(secret []byte, ttl time.Duration) *Tokens {
+	return &Tokens{secret: secret, ttl: ttl, now: time.Now}
+}
+
+// Issue returns a token for the teacher and the time it expires.
+func (t *Tokens) Issue(teacherID int) (string, time.Time, error) {
+	now := t.now()
+	expiresAt := now.Add(t.ttl)
+	payload, err := json.Marshal(claims{
+		Subject:   strconv.Itoa(teacherID),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + t.sign(unsigned), expiresAt, nil
+}
+
+// Verify checks the signature and expiry of a token and returns the teacher
+// ID it was issued for.
+func (t *Tokens) Verify(token string) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return 0, ErrInvalidToken
+	}
+	unsigned := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(t.sign(unsigned))) {
+		return 0, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return 0, ErrInvalidToken
+	}
+	if !t.now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return 0, ErrExpiredToken
+	}
+	teacherID, err := strconv.Atoi(c.Subject)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return teacherID, nil
+}
+
+func (t *Tokens) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTokens(secret string, now *time.Time) *Tokens {
+	t := NewTokens([]byte(secret), time.Hour)
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	now := time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)
+	tokens := newTestTokens("secret", &now)
+
+	token, expiresAt, err := tokens.Issue(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expires at %v, want an hour after %v", expiresAt, now)
+	}
+	teacherID, err := tokens.Verify(token)
+	if err != nil || teacherID != 7 {
+		t.Fatalf("Verify = %d, %v, want 7", teacherID, err)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	now := time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)
+	tokens := newTestTokens("secret", &now)
+	token, _, err := tokens.Issue(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(59 * time.Minute)
+	if _, err := tokens.Verify(token); err != nil {
+		t.Fatalf("token expired early: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("got %v after an hour, want ErrExpiredToken", err)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	now := time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)
+	tokens := newTestTokens("secret", &now)
+	token, _, err := tokens.Issue(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+
+	// A payload for teacher 1 that was never signed.
+	other, _, err := tokens.Issue(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2]
+	fromOtherSecret, _, err := newTestTokens("other secret", &now).Issue(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"empty":             "",
+		"garbage":           "not a token",
+		"swapped payload":   forged,
+		"changed signature": parts[0] + "." + parts[1] + "." + strings.ToUpper(parts[2]),
+		"no signature":      parts[0] + "." + parts[1] + ".",
+		"other header":      "eyJhbGciOiJub25lIn0." + parts[1] + "." + parts[2],
+		"other secret":      fromOtherSecret,
+	}
+	for name, token := range tests {
+		if _, err := tokens.Verify(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: got %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
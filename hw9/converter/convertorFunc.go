@@ -10,19 +10,28 @@ type ClassConverter struct{}
 func (c *ClassConverter) Convert(classes []entity.Class) []dto.Class {
 	dtoClasses := make([]dto.Class, len(classes))
 	for i, class := range classes {
-		dtoStudents := make([]string, len(class.Students))
-		for j, student := range class.Students {
-			dtoStudents[j] = student.Name
-		}
-		dtoClasses[i] = dto.Class{
-			ID:       class.ID,
-			Name:     class.Name,
-			Students: dtoStudents,
-		}
+		dtoClasses[i] = c.ConvertOne(class)
 	}
 	return dtoClasses
 }
 
+func (c *ClassConverter) ConvertOne(class entity.Class) dto.Class {
+	dtoStudents := make([]string, len(class.Students))
+	for j, student := range class.Students {
+		dtoStudents[j] = student.Name
+	}
+	dtoTeachers := make([]string, len(class.Teachers))
+	for j, teacher := range class.Teachers {
+		dtoTeachers[j] = teacher.Name
+	}
+	return dto.Class{
+		ID:       class.ID,
+		Name:     class.Name,
+		Students: dtoStudents,
+		Teachers: dtoTeachers,
+	}
+}
+
 type StudentConverter struct{}
 
 func (c *StudentConverter) Convert(student entity.Student) dto.Student {
@@ -34,3 +43,18 @@ func (c *StudentConverter) Convert(student entity.Student) dto.Student {
 
 	return dtoStudent
 }
+
+type TeacherConverter struct{}
+
+func (c *TeacherConverter) Convert(teacher entity.Teacher) dto.Teacher {
+	dtoClasses := make([]string, len(teacher.Classes))
+	for i, class := range teacher.Classes {
+		dtoClasses[i] = class.Name
+	}
+	return dto.Teacher{
+		ID:       teacher.ID,
+		Name:     teacher.Name,
+		Username: teacher.TeacherCred.Username,
+		Classes:  dtoClasses,
+	}
+}
@@ -1,9 +1,12 @@
 package dto
 
+import "time"
+
 type Class struct {
 	ID       int
 	Name     string
 	Students []string `json:"studentNames"`
+	Teachers []string `json:"teacherNames"`
 }
 
 type Student struct {
@@ -11,3 +14,40 @@ type Student struct {
 	Name   string
 	Grades map[string]float64
 }
+
+type Teacher struct {
+	ID       int
+	Name     string
+	Username string
+	Classes  []string `json:"classNames"`
+}
+
+// NewClass is the body of a class create or update.
+type NewClass struct {
+	Name string
+}
+
+// NewStudent is the body of a student create or update.
+type NewStudent struct {
+	Name   string
+	Grades map[string]float64
+}
+
+// NewTeacher is the body of a teacher create or update. The password is only
+// kept as a hash and never sent back. An update without a username or
+// password keeps the old one.
+type NewTeacher struct {
+	Name     string
+	Username string
+	Password string
+}
+
+type Login struct {
+	Username string
+	Password string
+}
+
+type Token struct {
+	Token     string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxPasswordLen is the most bcrypt hashes; it refuses longer passwords.
+const maxPasswordLen = 72
+
+var (
+	ErrEmptyUsername      = errors.New("username must not be empty")
+	ErrEmptyPassword      = errors.New("password must not be empty")
+	ErrPasswordTooLong    = fmt.Errorf("password must not be longer than %d bytes", maxPasswordLen)
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// NewTeacherCred hashes the password with bcrypt. The password itself is
+// never stored.
+func NewTeacherCred(username string, password string) (TeacherCred, error) {
+	if username == "" {
+		return TeacherCred{}, ErrEmptyUsername
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return TeacherCred{}, err
+	}
+	return TeacherCred{Username: username, PasswordHash: hash}, nil
+}
+
+func HashPassword(password string) ([]byte, error) {
+	if password == "" {
+		return nil, ErrEmptyPassword
+	}
+	if len(password) > maxPasswordLen {
+		return nil, ErrPasswordTooLong
+	}
+	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+}
+
+// CheckPassword compares in constant time.
+func (c TeacherCred) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword(c.PasswordHash, []byte(password)) == nil
+}
+
+// dummyCred is checked when a username is unknown, so that a wrong username
+// takes as long as a wrong password and usernames can't be guessed by timing.
+var dummyCred = sync.OnceValue(func() TeacherCred {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return TeacherCred{PasswordHash: hash}
+})
@@ -1,7 +1,10 @@
 package entity
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"sync"
 )
@@ -25,59 +28,427 @@ type Teacher struct {
 	TeacherCred TeacherCred
 }
 type TeacherCred struct {
-	Username string
-	Password string
+	Username     string
+	PasswordHash []byte
 }
 
-type Storage struct {
-	m           sync.Mutex
-	lastID      int
-	allClasses  map[int]Class
-	allStudents map[int]Student
+var (
+	ErrClassNotFound   = errors.New("class doesn't exist")
+	ErrStudentNotFound = errors.New("student doesn't exist")
+	ErrTeacherNotFound = errors.New("teacher doesn't exist")
+	ErrEmptyName       = errors.New("name must not be empty")
+)
+
+// classRecord keeps a class with the IDs of its students and teachers, so
+// that a student or a teacher is stored only once and every change to it is
+// seen by all of its classes.
+type classRecord struct {
+	ID         int
+	Name       string
+	StudentIDs []int
+	TeacherIDs []int
 }
 
-var MapAllClasses = make(map[int]Class)
-var Classes []Class
-var MapAllStudents = make(map[int]Student)
+type Storage struct {
+	m             sync.Mutex
+	lastClassID   int
+	lastStudentID int
+	lastTeacherID int
+	allClasses    map[int]classRecord
+	allStudents   map[int]Student
+	allTeachers   map[int]Teacher
+	usernames     map[string]int
+}
 
+// NewStorage returns an empty storage. Every storage has its own data, so
+// several of them can be used side by side; Seed fills one with sample data.
+// Getters return copies, so changing a result never changes the storage.
 func NewStorage() *Storage {
 	return &Storage{
-		allClasses:  MapAllClasses,
-		allStudents: MapAllStudents,
+		allClasses:  make(map[int]classRecord),
+		allStudents: make(map[int]Student),
+		allTeachers: make(map[int]Teacher),
+		usernames:   make(map[string]int),
+	}
+}
+
+// copyStudent gives the student its own grades.
+func copyStudent(student Student) Student {
+	student.Grades = maps.Clone(student.Grades)
+	return student
+}
+
+// class builds a class from its record. Its teachers have no classes, so that
+// the result has no cycles. s.m must be held.
+func (s *Storage) class(record classRecord) Class {
+	class := Class{ID: record.ID, Name: record.Name}
+	for _, id := range record.StudentIDs {
+		class.Students = append(class.Students, copyStudent(s.allStudents[id]))
+	}
+	for _, id := range record.TeacherIDs {
+		class.Teachers = append(class.Teachers, s.allTeachers[id])
+	}
+	return class
+}
+
+// teacher fills the classes of a teacher, without their teachers. s.m must
+// be held.
+func (s *Storage) teacher(teacher Teacher) Teacher {
+	teacher.TeacherCred.PasswordHash = slices.Clone(teacher.TeacherCred.PasswordHash)
+	teacher.Classes = nil
+	for _, record := range s.sortedClasses() {
+		if slices.Contains(record.TeacherIDs, teacher.ID) {
+			class := s.class(record)
+			class.Teachers = nil
+			teacher.Classes = append(teacher.Classes, class)
+		}
 	}
+	return teacher
+}
+
+// sortedClasses returns the class records by ID. s.m must be held.
+func (s *Storage) sortedClasses() []classRecord {
+	records := make([]classRecord, 0, len(s.allClasses))
+	for _, record := range s.allClasses {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records
 }
 
 func (s *Storage) GetAllClasses() []Class {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	sort.Slice(Classes, func(i, j int) bool {
-		return Classes[i].ID < Classes[j].ID
-	})
+	records := s.sortedClasses()
+	classes := make([]Class, len(records))
+	for i, record := range records {
+		classes[i] = s.class(record)
+	}
+	return classes
+}
+
+func (s *Storage) GetClass(id int) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
 
-	return Classes
+	record, exists := s.allClasses[id]
+	if !exists {
+		return Class{}, ErrClassNotFound
+	}
+	return s.class(record), nil
 }
 
-func (s *Storage) GetClass(id int) Class {
+// CreateClass stores a class with the name of c. Students and teachers are
+// added with EnrollStudent and AssignTeacher.
+func (s *Storage) CreateClass(c Class) (Class, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	class, exists := s.allClasses[id]
+	if c.Name == "" {
+		return Class{}, ErrEmptyName
+	}
+	s.lastClassID++
+	record := classRecord{ID: s.lastClassID, Name: c.Name}
+	s.allClasses[record.ID] = record
+	fmt.Printf("Created class. Last ID: %v\n", s.lastClassID)
+	return s.class(record), nil
+}
+
+// UpdateClass renames a class and keeps its students and teachers.
+func (s *Storage) UpdateClass(id int, c Class) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	record, exists := s.allClasses[id]
 	if !exists {
-		fmt.Printf("Class doesn't exist")
-		return Class{}
+		return Class{}, ErrClassNotFound
 	}
-	return class
+	if c.Name == "" {
+		return Class{}, ErrEmptyName
+	}
+	record.Name = c.Name
+	s.allClasses[id] = record
+	return s.class(record), nil
 }
 
-func (s *Storage) GetStudent(id int) Student {
+// DeleteClass removes a class. Its students and teachers stay.
+func (s *Storage) DeleteClass(id int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, exists := s.allClasses[id]; !exists {
+		return ErrClassNotFound
+	}
+	delete(s.allClasses, id)
+	return nil
+}
+
+func (s *Storage) GetAllStudents() []Student {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	students := make([]Student, 0, len(s.allStudents))
+	for _, student := range s.allStudents {
+		students = append(students, copyStudent(student))
+	}
+	sort.Slice(students, func(i, j int) bool {
+		return students[i].ID < students[j].ID
+	})
+	return students
+}
+
+func (s *Storage) GetStudent(id int) (Student, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
 	student, exists := s.allStudents[id]
 	if !exists {
-		fmt.Printf("Student doesn't exist")
-		return Student{}
+		return Student{}, ErrStudentNotFound
 	}
-	return student
+	return copyStudent(student), nil
+}
+
+func (s *Storage) CreateStudent(student Student) (Student, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if student.Name == "" {
+		return Student{}, ErrEmptyName
+	}
+	s.lastStudentID++
+	student.ID = s.lastStudentID
+	s.allStudents[student.ID] = copyStudent(student)
+	fmt.Printf("Created student. Last ID: %v\n", s.lastStudentID)
+	return copyStudent(student), nil
+}
+
+// UpdateStudent replaces the name and grades of a student. The classes of
+// the student see the change.
+func (s *Storage) UpdateStudent(id int, student Student) (Student, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, exists := s.allStudents[id]; !exists {
+		return Student{}, ErrStudentNotFound
+	}
+	if student.Name == "" {
+		return Student{}, ErrEmptyName
+	}
+	student.ID = id
+	s.allStudents[id] = copyStudent(student)
+	return copyStudent(student), nil
+}
+
+// DeleteStudent removes a student and takes them out of every class.
+func (s *Storage) DeleteStudent(id int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, exists := s.allStudents[id]; !exists {
+		return ErrStudentNotFound
+	}
+	delete(s.allStudents, id)
+	for classID, record := range s.allClasses {
+		record.StudentIDs = slices.DeleteFunc(record.StudentIDs, func(studentID int) bool {
+			return studentID == id
+		})
+		s.allClasses[classID] = record
+	}
+	return nil
+}
+
+func (s *Storage) GetAllTeachers() []Teacher {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	teachers := make([]Teacher, 0, len(s.allTeachers))
+	for _, teacher := range s.allTeachers {
+		teachers = append(teachers, s.teacher(teacher))
+	}
+	sort.Slice(teachers, func(i, j int) bool {
+		return teachers[i].ID < teachers[j].ID
+	})
+	return teachers
+}
+
+func (s *Storage) GetTeacher(id int) (Teacher, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	teacher, exists := s.allTeachers[id]
+	if !exists {
+		return Teacher{}, ErrTeacherNotFound
+	}
+	return s.teacher(teacher), nil
+}
+
+// CreateTeacher stores a teacher with credentials made by NewTeacherCred.
+// Usernames are unique. Classes are added with AssignTeacher.
+func (s *Storage) CreateTeacher(teacher Teacher) (Teacher, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if teacher.Name == "" {
+		return Teacher{}, ErrEmptyName
+	}
+	if teacher.TeacherCred.Username == "" {
+		return Teacher{}, ErrEmptyUsername
+	}
+	if len(teacher.TeacherCred.PasswordHash) == 0 {
+		return Teacher{}, ErrEmptyPassword
+	}
+	if _, taken := s.usernames[teacher.TeacherCred.Username]; taken {
+		return Teacher{}, ErrUsernameTaken
+	}
+	s.lastTeacherID++
+	teacher.ID = s.lastTeacherID
+	teacher.Classes = nil
+	s.allTeachers[teacher.ID] = teacher
+	s.usernames[teacher.TeacherCred.Username] = teacher.ID
+	fmt.Printf("Created teacher. Last ID: %v\n", s.lastTeacherID)
+	return s.teacher(teacher), nil
+}
+
+// UpdateTeacher replaces the name of a teacher and keeps their classes. An
+// empty username or password hash keeps the old one.
+func (s *Storage) UpdateTeacher(id int, teacher Teacher) (Teacher, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	old, exists := s.allTeachers[id]
+	if !exists {
+		return Teacher{}, ErrTeacherNotFound
+	}
+	if teacher.Name == "" {
+		return Teacher{}, ErrEmptyName
+	}
+	if teacher.TeacherCred.Username == "" {
+		teacher.TeacherCred.Username = old.TeacherCred.Username
+	}
+	if len(teacher.TeacherCred.PasswordHash) == 0 {
+		teacher.TeacherCred.PasswordHash = old.TeacherCred.PasswordHash
+	}
+	if owner, taken := s.usernames[teacher.TeacherCred.Username]; taken && owner != id {
+		return Teacher{}, ErrUsernameTaken
+	}
+	teacher.ID = id
+	teacher.Classes = nil
+	s.allTeachers[id] = teacher
+	delete(s.usernames, old.TeacherCred.Username)
+	s.usernames[teacher.TeacherCred.Username] = id
+	return s.teacher(teacher), nil
+}
+
+// DeleteTeacher removes a teacher and takes them off every class.
+func (s *Storage) DeleteTeacher(id int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	teacher, exists := s.allTeachers[id]
+	if !exists {
+		return ErrTeacherNotFound
+	}
+	delete(s.allTeachers, id)
+	delete(s.usernames, teacher.TeacherCred.Username)
+	for classID, record := range s.allClasses {
+		record.TeacherIDs = slices.DeleteFunc(record.TeacherIDs, func(teacherID int) bool {
+			return teacherID == id
+		})
+		s.allClasses[classID] = record
+	}
+	return nil
+}
+
+// Authenticate returns the teacher with the username if the password is
+// right, and ErrInvalidCredentials otherwise.
+func (s *Storage) Authenticate(username string, password string) (Teacher, error) {
+	s.m.Lock()
+	teacher, exists := s.allTeachers[s.usernames[username]]
+	s.m.Unlock()
+
+	// The hash is checked without the lock, as bcrypt is slow on purpose.
+	cred := teacher.TeacherCred
+	if !exists {
+		cred = dummyCred()
+	}
+	if !cred.CheckPassword(password) || !exists {
+		return Teacher{}, ErrInvalidCredentials
+	}
+	return s.GetTeacher(teacher.ID)
+}
+
+// EnrollStudent adds a student to a class. Enrolling twice changes nothing.
+func (s *Storage) EnrollStudent(classID int, studentID int) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	record, exists := s.allClasses[classID]
+	if !exists {
+		return Class{}, ErrClassNotFound
+	}
+	if _, exists := s.allStudents[studentID]; !exists {
+		return Class{}, ErrStudentNotFound
+	}
+	if !slices.Contains(record.StudentIDs, studentID) {
+		record.StudentIDs = append(record.StudentIDs, studentID)
+		s.allClasses[classID] = record
+	}
+	return s.class(record), nil
+}
+
+func (s *Storage) UnenrollStudent(classID int, studentID int) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	record, exists := s.allClasses[classID]
+	if !exists {
+		return Class{}, ErrClassNotFound
+	}
+	i := slices.Index(record.StudentIDs, studentID)
+	if i < 0 {
+		return Class{}, ErrStudentNotFound
+	}
+	record.StudentIDs = slices.Delete(record.StudentIDs, i, i+1)
+	s.allClasses[classID] = record
+	return s.class(record), nil
+}
+
+// AssignTeacher makes a teacher teach a class. Assigning twice changes
+// nothing.
+func (s *Storage) AssignTeacher(classID int, teacherID int) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	record, exists := s.allClasses[classID]
+	if !exists {
+		return Class{}, ErrClassNotFound
+	}
+	if _, exists := s.allTeachers[teacherID]; !exists {
+		return Class{}, ErrTeacherNotFound
+	}
+	if !slices.Contains(record.TeacherIDs, teacherID) {
+		record.TeacherIDs = append(record.TeacherIDs, teacherID)
+		s.allClasses[classID] = record
+	}
+	return s.class(record), nil
+}
+
+func (s *Storage) UnassignTeacher(classID int, teacherID int) (Class, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	record, exists := s.allClasses[classID]
+	if !exists {
+		return Class{}, ErrClassNotFound
+	}
+	i := slices.Index(record.TeacherIDs, teacherID)
+	if i < 0 {
+		return Class{}, ErrTeacherNotFound
+	}
+	record.TeacherIDs = slices.Delete(record.TeacherIDs, i, i+1)
+	s.allClasses[classID] = record
+	return s.class(record), nil
 }
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStoragesAreIndependent(t *testing.T) {
+	seeded, empty := NewStorage(), NewStorage()
+	if err := Seed(seeded); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(empty.GetAllStudents()); got != 0 {
+		t.Fatalf("empty storage has %d students", got)
+	}
+	if got := len(seeded.GetAllStudents()); got != 10 {
+		t.Fatalf("seeded storage has %d students, want 10", got)
+	}
+
+	student, err := empty.CreateStudent(Student{Name: "Ann Lee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if student.ID != 1 {
+		t.Errorf("first student of a new storage has ID %d, want 1", student.ID)
+	}
+	if got, _ := seeded.GetStudent(1); got.Name != "John Doe" {
+		t.Errorf("seeded student 1 is %q, want John Doe", got.Name)
+	}
+	if got := len(seeded.GetAllStudents()); got != 10 {
+		t.Errorf("seeded storage has %d students after a create in another", got)
+	}
+
+	// The same username may be taken in each storage.
+	cred, err := NewTeacherCred("smith", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := empty.CreateTeacher(Teacher{Name: "Ms. Smith", TeacherCred: cred}); err != nil {
+		t.Fatalf("username of another storage is taken: %v", err)
+	}
+	if _, err := seeded.Authenticate("smith", "secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("password of another storage accepted: %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := NewStorage()
+	cred, err := NewTeacherCred("smith", "1111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.CreateTeacher(Teacher{Name: "Mr. Smith", TeacherCred: cred})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teacher, err := s.Authenticate("smith", "1111")
+	if err != nil || teacher.ID != created.ID {
+		t.Fatalf("Authenticate = %+v, %v, want teacher %d", teacher, err, created.ID)
+	}
+	for _, login := range [][2]string{{"smith", "2222"}, {"smith", ""}, {"nobody", "1111"}, {"", ""}} {
+		if _, err := s.Authenticate(login[0], login[1]); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%q, %q) = %v, want ErrInvalidCredentials", login[0], login[1], err)
+		}
+	}
+
+	if _, err := s.CreateTeacher(Teacher{Name: "Ms. Smith", TeacherCred: cred}); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("second smith: got %v, want ErrUsernameTaken", err)
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		password string
+		wantErr  error
+	}{
+		{"", ErrEmptyPassword},
+		{strings.Repeat("a", maxPasswordLen), nil},
+		{strings.Repeat("a", maxPasswordLen+1), ErrPasswordTooLong},
+		{strings.Repeat("ä", maxPasswordLen/2+1), ErrPasswordTooLong},
+	}
+	for _, tt := range tests {
+		hash, err := HashPassword(tt.password)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("HashPassword of %d bytes: got %v, want %v", len(tt.password), err, tt.wantErr)
+			continue
+		}
+		if err == nil && !(TeacherCred{PasswordHash: hash}).CheckPassword(tt.password) {
+			t.Errorf("hash of %d bytes doesn't match its password", len(tt.password))
+		}
+	}
+	if _, err := NewTeacherCred("", "1111"); !errors.Is(err, ErrEmptyUsername) {
+		t.Errorf("empty username: got %v", err)
+	}
+}
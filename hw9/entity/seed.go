@@ -0,0 +1,76 @@
+package entity
+
+// Seed fills an empty storage with the sample school: ten students, five
+// classes and their three teachers. The IDs start at 1 in the order below.
+func Seed(s *Storage) error {
+	students := []Student{
+		{Name: "John Doe", Grades: map[string]float64{"Math": 90, "Science": 85}},
+		{Name: "Jane Smith", Grades: map[string]float64{"Math": 92, "Science": 88}},
+		{Name: "Emily Johnson", Grades: map[string]float64{"Math": 85, "Science": 89}},
+		{Name: "Michael Brown", Grades: map[string]float64{"Math": 87, "Science": 84}},
+		{Name: "Sarah Davis", Grades: map[string]float64{"Math": 93, "Science": 91}},
+		{Name: "David Wilson", Grades: map[string]float64{"Math": 78, "Science": 80}},
+		{Name: "Laura Martinez", Grades: map[string]float64{"Math": 88, "Science": 86}},
+		{Name: "James Garcia", Grades: map[string]float64{"Math": 91, "Science": 87}},
+		{Name: "Sophia Martinez", Grades: map[string]float64{"Math": 90, "Science": 92}},
+		{Name: "Christopher Lee", Grades: map[string]float64{"Math": 84, "Science": 83}},
+	}
+	classes := []struct {
+		name     string
+		students []int
+	}{
+		{"Physics 101", []int{1, 2, 3, 4, 5}},
+		{"Chemistry 101", []int{6, 7, 8, 9, 10}},
+		{"Biology 101", []int{1, 3, 5, 7, 9}},
+		{"Math 101", []int{2, 4, 6, 8, 10}},
+		{"History 101", []int{1, 2, 4, 6, 8}},
+	}
+	teachers := []struct {
+		name     string
+		username string
+		password string
+		classes  []int
+	}{
+		{"Mr. Smith", "smith", "1111", []int{1, 2}},
+		{"Ms. Johnson", "johnson", "2222", []int{3, 4}},
+		{"Dr. Brown", "brown", "3333", []int{5}},
+	}
+
+	studentIDs := make([]int, len(students))
+	for i, student := range students {
+		created, err := s.CreateStudent(student)
+		if err != nil {
+			return err
+		}
+		studentIDs[i] = created.ID
+	}
+	classIDs := make([]int, len(classes))
+	for i, class := range classes {
+		created, err := s.CreateClass(Class{Name: class.name})
+		if err != nil {
+			return err
+		}
+		classIDs[i] = created.ID
+		for _, student := range class.students {
+			if _, err := s.EnrollStudent(created.ID, studentIDs[student-1]); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range teachers {
+		cred, err := NewTeacherCred(t.username, t.password)
+		if err != nil {
+			return err
+		}
+		created, err := s.CreateTeacher(Teacher{Name: t.name, TeacherCred: cred})
+		if err != nil {
+			return err
+		}
+		for _, class := range t.classes {
+			if _, err := s.AssignTeacher(classIDs[class-1], created.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -1,3 +1,5 @@
 module GoLangProjector/hw9
 
 go 1.22.3
+
+require golang.org/x/crypto v0.32.0
@@ -1,34 +1,127 @@
 package main
 
 import (
+	"GoLangProjector/hw9/auth"
 	"GoLangProjector/hw9/converter"
 	"GoLangProjector/hw9/entity"
 	resursestype "GoLangProjector/hw9/resurses"
+	"crypto/rand"
 	"fmt"
 	"net/http"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
-func main() {
+const tokenTTL = time.Hour
 
-	mux := http.NewServeMux()
+func main() {
+	storage := entity.NewStorage()
+	if err := entity.Seed(storage); err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
 
-	mux.HandleFunc("/classes", classResource.GetAllClasses)
-	mux.HandleFunc("/classes/{id}", classResource.GetClass)
-	mux.HandleFunc("/students/{id}", checkAuth(studentResource.GetUserById))
+	secret, err := tokenSecret()
+	if err != nil {
+		fmt.Println("Error is occurred: ", err.Error())
+		return
+	}
+	tokens := auth.NewTokens(secret, tokenTTL)
 
-	err := http.ListenAndServe(":8080", mux)
+	err = http.ListenAndServe(":8080", newMux(storage, tokens))
 	if err != nil {
 		fmt.Println("Error is occurred: ", err.Error())
 		return
 	}
 }
 
-func checkAuth(next http.HandlerFunc) http.HandlerFunc {
+// newMux registers every route of the school API on a new mux.
+func newMux(storage resursestype.Storage, tokens *auth.Tokens) *http.ServeMux {
+	classResource := resursestype.ClassResource{
+		S:  storage,
+		Cc: &converter.ClassConverter{},
+	}
+	studentResource := resursestype.StudentResource{
+		S:  storage,
+		Sc: &converter.StudentConverter{},
+	}
+	teacherResource := resursestype.TeacherResource{
+		S:  storage,
+		Tc: &converter.TeacherConverter{},
+	}
+	authResource := resursestype.AuthResource{
+		S:      storage,
+		Tokens: tokens,
+	}
+	g := guard{s: storage, tokens: tokens}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /login", authResource.Login)
+
+	mux.HandleFunc("GET /classes", classResource.GetAllClasses)
+	mux.HandleFunc("GET /classes/{id}", classResource.GetClass)
+	mux.HandleFunc("POST /classes", g.checkTeacher(classResource.CreateClass))
+	mux.HandleFunc("PUT /classes/{id}", g.checkClassTeacher(classResource.UpdateClass))
+	mux.HandleFunc("DELETE /classes/{id}", g.checkClassTeacher(classResource.DeleteClass))
+	mux.HandleFunc("PUT /classes/{id}/students/{studentId}", g.checkClassTeacher(classResource.EnrollStudent))
+	mux.HandleFunc("DELETE /classes/{id}/students/{studentId}", g.checkClassTeacher(classResource.UnenrollStudent))
+	mux.HandleFunc("PUT /classes/{id}/teachers/{teacherId}", g.checkClassTeacher(classResource.AssignTeacher))
+	mux.HandleFunc("DELETE /classes/{id}/teachers/{teacherId}", g.checkClassTeacher(classResource.UnassignTeacher))
+
+	mux.HandleFunc("GET /students", g.checkTeacher(studentResource.GetAllStudents))
+	mux.HandleFunc("GET /students/{id}", g.checkAuth(studentResource.GetUserById))
+	mux.HandleFunc("POST /students", g.checkTeacher(studentResource.CreateStudent))
+	mux.HandleFunc("PUT /students/{id}", g.checkAuth(studentResource.UpdateStudent))
+	mux.HandleFunc("DELETE /students/{id}", g.checkAuth(studentResource.DeleteStudent))
+
+	mux.HandleFunc("GET /teachers", g.checkTeacher(teacherResource.GetAllTeachers))
+	mux.HandleFunc("GET /teachers/{id}", g.checkTeacher(teacherResource.GetTeacher))
+	mux.HandleFunc("POST /teachers", g.checkTeacher(teacherResource.CreateTeacher))
+	mux.HandleFunc("PUT /teachers/{id}", g.checkSelf(teacherResource.UpdateTeacher))
+	mux.HandleFunc("DELETE /teachers/{id}", g.checkSelf(teacherResource.DeleteTeacher))
+	return mux
+}
+
+// guard lets teachers in with either Basic auth or a bearer token from
+// POST /login.
+type guard struct {
+	s      resursestype.Storage
+	tokens *auth.Tokens
+}
+
+// teacher returns the teacher who sent the request.
+func (g guard) teacher(r *http.Request) (entity.Teacher, bool) {
+	if username, password, ok := r.BasicAuth(); ok {
+		teacher, err := g.s.Authenticate(username, password)
+		return teacher, err == nil
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return entity.Teacher{}, false
+	}
+	teacherID, err := g.tokens.Verify(token)
+	if err != nil {
+		return entity.Teacher{}, false
+	}
+	teacher, err := g.s.GetTeacher(teacherID)
+	return teacher, err == nil
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="school", Bearer`)
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+// checkAuth lets through the teachers of the student in the path.
+func (g guard) checkAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		username, password, ok := r.BasicAuth()
+		teacher, ok := g.teacher(r)
 		if !ok {
-			w.WriteHeader(http.StatusUnauthorized)
+			unauthorized(w)
 			return
 		}
 		idUser := r.PathValue("id")
@@ -37,24 +130,16 @@ func checkAuth(next http.HandlerFunc) http.HandlerFunc {
 			http.Error(w, "Invalid student ID", http.StatusBadRequest)
 			return
 		}
-		var teachersList []entity.Teacher
-		classes := classResource.S.GetAllClasses()
-		for _, class := range classes {
-			students := class.Students
-			for _, student := range students {
+		var isAuthorised bool
+		for _, class := range teacher.Classes {
+			for _, student := range class.Students {
 				if student.ID == id {
-					teachersList = append(teachersList, class.Teachers...)
+					isAuthorised = true
 				}
 			}
 		}
-		var isAuthorised bool
-		for _, teacher := range teachersList {
-			if teacher.TeacherCred.Username == username && teacher.TeacherCred.Password == password {
-				isAuthorised = true
-			}
-		}
 		if !isAuthorised {
-			w.WriteHeader(http.StatusUnauthorized)
+			w.WriteHeader(http.StatusForbidden)
 			return
 		}
 
@@ -62,133 +147,72 @@ func checkAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-var teachers []entity.Teacher
-var classResource resursestype.ClassResource
-var studentResource resursestype.StudentResource
-
-func init() {
-
-	students := []entity.Student{
-		{ID: 1, Name: "John Doe", Grades: map[string]float64{"Math": 90, "Science": 85}},
-		{ID: 2, Name: "Jane Smith", Grades: map[string]float64{"Math": 92, "Science": 88}},
-		{ID: 3, Name: "Emily Johnson", Grades: map[string]float64{"Math": 85, "Science": 89}},
-		{ID: 4, Name: "Michael Brown", Grades: map[string]float64{"Math": 87, "Science": 84}},
-		{ID: 5, Name: "Sarah Davis", Grades: map[string]float64{"Math": 93, "Science": 91}},
-		{ID: 6, Name: "David Wilson", Grades: map[string]float64{"Math": 78, "Science": 80}},
-		{ID: 7, Name: "Laura Martinez", Grades: map[string]float64{"Math": 88, "Science": 86}},
-		{ID: 8, Name: "James Garcia", Grades: map[string]float64{"Math": 91, "Science": 87}},
-		{ID: 9, Name: "Sophia Martinez", Grades: map[string]float64{"Math": 90, "Science": 92}},
-		{ID: 10, Name: "Christopher Lee", Grades: map[string]float64{"Math": 84, "Science": 83}},
-	}
-
-	entity.Classes = []entity.Class{
-		{
-			ID:   2,
-			Name: "Chemistry 101",
-			Students: []entity.Student{
-				students[5], students[6], students[7], students[8], students[9],
-			},
-		},
-		{
-			ID:   1,
-			Name: "Physics 101",
-			Students: []entity.Student{
-				students[0], students[1], students[2], students[3], students[4],
-			},
-		},
-		{
-			ID:   3,
-			Name: "Biology 101",
-			Students: []entity.Student{
-				students[0], students[2], students[4], students[6], students[8],
-			},
-		},
-		{
-			ID:   4,
-			Name: "Math 101",
-			Students: []entity.Student{
-				students[1], students[3], students[5], students[7], students[9],
-			},
-		},
-		{
-			ID:   5,
-			Name: "History 101",
-			Students: []entity.Student{
-				students[0], students[1], students[3], students[5], students[7],
-			},
-		},
-	}
-
-	for _, c := range entity.Classes {
-		entity.MapAllClasses[c.ID] = c
-	}
-
-	for _, s := range students {
-		entity.MapAllStudents[s.ID] = s
-	}
-	storage := entity.NewStorage()
+// checkTeacher lets any teacher through, for changes that are not tied to
+// one student.
+func (g guard) checkTeacher(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := g.teacher(r); !ok {
+			unauthorized(w)
+			return
+		}
 
-	classResource = resursestype.ClassResource{
-		S:  storage,
-		Cc: &converter.ClassConverter{},
+		next.ServeHTTP(w, r)
 	}
-	studentResource = resursestype.StudentResource{
-		S:  storage,
-		Sc: &converter.StudentConverter{},
+}
+
+// checkClassTeacher lets through the teachers of the class in the path. A
+// class without teachers is open to any teacher, so that a new class can get
+// its first one.
+func (g guard) checkClassTeacher(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teacher, ok := g.teacher(r)
+		if !ok {
+			unauthorized(w)
+			return
+		}
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid class ID", http.StatusBadRequest)
+			return
+		}
+		class, err := g.s.GetClass(id)
+		if err == nil && len(class.Teachers) > 0 &&
+			!slices.ContainsFunc(class.Teachers, func(t entity.Teacher) bool { return t.ID == teacher.ID }) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
 	}
+}
 
-	teachers = []entity.Teacher{
-		{ID: 1, Name: "Mr. Smith",
-			TeacherCred: entity.TeacherCred{
-				Username: "Olena",
-				Password: "1111",
-			}},
-		{ID: 2, Name: "Ms. Johnson",
-			TeacherCred: entity.TeacherCred{
-				Username: "Olena",
-				Password: "2222",
-			}},
-		{ID: 3, Name: "Dr. Brown",
-			TeacherCred: entity.TeacherCred{
-				Username: "Olena",
-				Password: "3333",
-			}},
-	}
-
-	teachers[0].Classes = []entity.Class{entity.Classes[0], entity.Classes[1]}
-	teachers[1].Classes = []entity.Class{entity.Classes[2], entity.Classes[3]}
-	teachers[2].Classes = []entity.Class{entity.Classes[4]}
-
-	entity.Classes[0].Teachers = []entity.Teacher{teachers[0]}
-	entity.Classes[1].Teachers = []entity.Teacher{teachers[0]}
-	entity.Classes[2].Teachers = []entity.Teacher{teachers[1]}
-	entity.Classes[3].Teachers = []entity.Teacher{teachers[1]}
-	entity.Classes[4].Teachers = []entity.Teacher{teachers[2]}
+// checkSelf lets a teacher change or delete only their own account.
+func (g guard) checkSelf(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teacher, ok := g.teacher(r)
+		if !ok {
+			unauthorized(w)
+			return
+		}
+		if r.PathValue("id") != strconv.Itoa(teacher.ID) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
 
+		next.ServeHTTP(w, r)
+	}
 }
 
-//func printClassAndTeacherDetails() {
-//	//Printing class details
-//	for _, class := range entity.Classes {
-//		fmt.Printf("Class ID: %d, Name: %s\n", class.ID, class.Name)
-//		fmt.Println("Students:")
-//		for _, student := range class.Students {
-//			fmt.Printf("\tStudent ID: %d, Name: %s\n", student.ID, student.Name)
-//		}
-//		fmt.Println("Teachers:")
-//		for _, teacher := range class.Teachers {
-//			fmt.Printf("\tTeacher ID: %d, Name: %s\n", teacher.ID, teacher.Name)
-//		}
-//		fmt.Println()
-//	}
-//
-//	// Printing teacher details
-//	for _, teacher := range teachers {
-//		fmt.Printf("Teacher ID: %d, Name: %s\n", teacher.ID, teacher.Name)
-//		fmt.Println("Classes:")
-//		for _, class := range teacher.Classes {
-//			fmt.Printf("\tClass ID: %d, Name: %s\n", class.ID, class.Name)
-//		}
-//		fmt.Println()
-//	}
-//}
+// tokenSecret comes from HW9_TOKEN_SECRET. Without it a random secret is
+// used and tokens stop working when the server restarts.
+func tokenSecret() ([]byte, error) {
+	if secret := os.Getenv("HW9_TOKEN_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	fmt.Println("HW9_TOKEN_SECRET is not set, tokens are valid until the server restarts")
+	return secret, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"GoLangProjector/hw9/auth"
+	"GoLangProjector/hw9/dto"
+	"GoLangProjector/hw9/entity"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves a freshly seeded school. Smith teaches classes 1 and
+// 2, Johnson 3 and 4, Brown class 5, which doesn't have student 3.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	storage := entity.NewStorage()
+	if err := entity.Seed(storage); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(newMux(storage, auth.NewTokens([]byte("test secret"), time.Hour)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type request struct {
+	method   string
+	path     string
+	user     string
+	password string
+	token    string
+	body     string
+}
+
+func (r request) send(t *testing.T, server *httptest.Server) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(r.method, server.URL+r.path, strings.NewReader(r.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.user != "" {
+		req.SetBasicAuth(r.user, r.password)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestGuards(t *testing.T) {
+	tests := []struct {
+		name string
+		req  request
+		want int
+	}{
+		{"teachers need a login", request{method: "GET", path: "/teachers"}, http.StatusUnauthorized},
+		{"teacher needs a login", request{method: "GET", path: "/teachers/1"}, http.StatusUnauthorized},
+		{"teachers with a login", request{method: "GET", path: "/teachers", user: "smith", password: "1111"}, http.StatusOK},
+		{"wrong password", request{method: "GET", path: "/teachers", user: "smith", password: "2222"}, http.StatusUnauthorized},
+		{"unknown user", request{method: "GET", path: "/teachers", user: "nobody", password: "1111"}, http.StatusUnauthorized},
+		{"invalid token", request{method: "GET", path: "/teachers", token: "not.a.token"}, http.StatusUnauthorized},
+
+		{"own student", request{method: "GET", path: "/students/3", user: "johnson", password: "2222"}, http.StatusOK},
+		{"other student", request{method: "GET", path: "/students/3", user: "brown", password: "3333"}, http.StatusForbidden},
+		{"update other student", request{method: "PUT", path: "/students/3", user: "brown", password: "3333",
+			body: `{"Name":"Emily Johnson"}`}, http.StatusForbidden},
+		{"delete other student", request{method: "DELETE", path: "/students/3", user: "brown", password: "3333"}, http.StatusForbidden},
+		{"delete own student", request{method: "DELETE", path: "/students/3", user: "johnson", password: "2222"}, http.StatusNoContent},
+
+		{"join other class", request{method: "PUT", path: "/classes/3/teachers/1", user: "smith", password: "1111"}, http.StatusForbidden},
+		{"enroll in other class", request{method: "PUT", path: "/classes/3/students/2", user: "smith", password: "1111"}, http.StatusForbidden},
+		{"rename other class", request{method: "PUT", path: "/classes/3", user: "smith", password: "1111",
+			body: `{"Name":"x"}`}, http.StatusForbidden},
+		{"enroll in own class", request{method: "PUT", path: "/classes/1/students/6", user: "smith", password: "1111"}, http.StatusOK},
+		{"add colleague to own class", request{method: "PUT", path: "/classes/1/teachers/3", user: "smith", password: "1111"}, http.StatusOK},
+
+		{"update other teacher", request{method: "PUT", path: "/teachers/2", user: "smith", password: "1111",
+			body: `{"Name":"x"}`}, http.StatusForbidden},
+		{"delete other teacher", request{method: "DELETE", path: "/teachers/2", user: "smith", password: "1111"}, http.StatusForbidden},
+		{"delete self", request{method: "DELETE", path: "/teachers/3", user: "brown", password: "3333"}, http.StatusNoContent},
+
+		{"too long password", request{method: "POST", path: "/teachers", user: "smith", password: "1111",
+			body: `{"Name":"Ms. Long","Username":"long","Password":"` + strings.Repeat("a", 73) + `"}`}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			if got := tt.req.send(t, server).StatusCode; got != tt.want {
+				t.Errorf("%s %s as %q: got %d, want %d", tt.req.method, tt.req.path, tt.req.user, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClassTakesFirstTeacher(t *testing.T) {
+	server := newTestServer(t)
+	create := request{method: "POST", path: "/classes", user: "smith", password: "1111", body: `{"Name":"Art"}`}
+	if got := create.send(t, server).StatusCode; got != http.StatusCreated {
+		t.Fatalf("create class: got %d", got)
+	}
+	steps := []struct {
+		req  request
+		want int
+	}{
+		{request{method: "PUT", path: "/classes/6/teachers/2", user: "johnson", password: "2222"}, http.StatusOK},
+		{request{method: "PUT", path: "/classes/6/students/1", user: "smith", password: "1111"}, http.StatusForbidden},
+		{request{method: "PUT", path: "/classes/6/students/1", user: "johnson", password: "2222"}, http.StatusOK},
+	}
+	for _, step := range steps {
+		if got := step.req.send(t, server).StatusCode; got != step.want {
+			t.Errorf("%s %s as %s: got %d, want %d", step.req.method, step.req.path, step.req.user, got, step.want)
+		}
+	}
+}
+
+func TestLoginToken(t *testing.T) {
+	server := newTestServer(t)
+
+	wrong := request{method: "POST", path: "/login", body: `{"Username":"smith","Password":"2222"}`}
+	if got := wrong.send(t, server).StatusCode; got != http.StatusUnauthorized {
+		t.Fatalf("login with a wrong password: got %d", got)
+	}
+
+	resp := request{method: "POST", path: "/login", body: `{"Username":"smith","Password":"1111"}`}.send(t, server)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login: got %d", resp.StatusCode)
+	}
+	var token dto.Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatal(err)
+	}
+
+	self := request{method: "PUT", path: "/teachers/1", token: token.Token, body: `{"Name":"Mr. J. Smith"}`}
+	if got := self.send(t, server).StatusCode; got != http.StatusOK {
+		t.Errorf("update self with a token: got %d", got)
+	}
+	other := request{method: "DELETE", path: "/teachers/2", token: token.Token}
+	if got := other.send(t, server).StatusCode; got != http.StatusForbidden {
+		t.Errorf("delete another teacher with a token: got %d", got)
+	}
+	tampered := request{method: "GET", path: "/teachers", token: token.Token + "x"}
+	if got := tampered.send(t, server).StatusCode; got != http.StatusUnauthorized {
+		t.Errorf("tampered token: got %d", got)
+	}
+}
@@ -6,6 +6,27 @@ import (
 
 type Storage interface {
 	GetAllClasses() []entity.Class
-	GetClass(id int) entity.Class
-	GetStudent(id int) entity.Student
+	GetClass(id int) (entity.Class, error)
+	CreateClass(c entity.Class) (entity.Class, error)
+	UpdateClass(id int, c entity.Class) (entity.Class, error)
+	DeleteClass(id int) error
+	EnrollStudent(classID int, studentID int) (entity.Class, error)
+	UnenrollStudent(classID int, studentID int) (entity.Class, error)
+	AssignTeacher(classID int, teacherID int) (entity.Class, error)
+	UnassignTeacher(classID int, teacherID int) (entity.Class, error)
+
+	GetAllStudents() []entity.Student
+	GetStudent(id int) (entity.Student, error)
+	CreateStudent(student entity.Student) (entity.Student, error)
+	UpdateStudent(id int, student entity.Student) (entity.Student, error)
+	DeleteStudent(id int) error
+
+	GetAllTeachers() []entity.Teacher
+	GetTeacher(id int) (entity.Teacher, error)
+	CreateTeacher(teacher entity.Teacher) (entity.Teacher, error)
+	UpdateTeacher(id int, teacher entity.Teacher) (entity.Teacher, error)
+	DeleteTeacher(id int) error
+	Authenticate(username string, password string) (entity.Teacher, error)
 }
+
+var _ Storage = (*entity.Storage)(nil)
@@ -0,0 +1,32 @@
+package resursestype
+
+import (
+	"GoLangProjector/hw9/auth"
+	"GoLangProjector/hw9/dto"
+	"net/http"
+)
+
+type AuthResource struct {
+	S      Storage
+	Tokens *auth.Tokens
+}
+
+// Login checks a teacher's username and password and returns a bearer token.
+func (aR *AuthResource) Login(w http.ResponseWriter, r *http.Request) {
+	var login dto.Login
+	if !decode(w, r, &login) {
+		return
+	}
+
+	teacher, err := aR.S.Authenticate(login.Username, login.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	token, expiresAt, err := aR.Tokens.Issue(teacher.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.Token{Token: token, ExpiresAt: expiresAt})
+}
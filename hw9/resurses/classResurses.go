@@ -2,43 +2,113 @@ package resursestype
 
 import (
 	"GoLangProjector/hw9/converter"
+	"GoLangProjector/hw9/dto"
 	"GoLangProjector/hw9/entity"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"strconv"
 )
 
 type ClassResource struct {
-	S  *entity.Storage
+	S  Storage
 	Cc *converter.ClassConverter
 }
 
 func (cR *ClassResource) GetAllClasses(w http.ResponseWriter, r *http.Request) {
 	classes := cR.S.GetAllClasses()
-	dtoClasses := cR.Cc.Convert(classes)
+	writeJSON(w, http.StatusOK, cR.Cc.Convert(classes))
+}
+
+func (cR *ClassResource) GetClass(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "class")
+	if !ok {
+		return
+	}
 
-	err := json.NewEncoder(w).Encode(dtoClasses)
+	class, err := cR.S.GetClass(id)
 	if err != nil {
-		fmt.Printf("Failed to encode: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, cR.Cc.ConvertOne(class))
 }
 
-func (cR *ClassResource) GetClass(w http.ResponseWriter, r *http.Request) {
-	idClass := r.PathValue("id")
-	id, err := strconv.Atoi(idClass)
+func (cR *ClassResource) CreateClass(w http.ResponseWriter, r *http.Request) {
+	var newClass dto.NewClass
+	if !decode(w, r, &newClass) {
+		return
+	}
+
+	class, err := cR.S.CreateClass(entity.Class{Name: newClass.Name})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, cR.Cc.ConvertOne(class))
+}
+
+func (cR *ClassResource) UpdateClass(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "class")
+	if !ok {
+		return
+	}
+	var newClass dto.NewClass
+	if !decode(w, r, &newClass) {
+		return
+	}
+
+	class, err := cR.S.UpdateClass(id, entity.Class{Name: newClass.Name})
 	if err != nil {
-		http.Error(w, "Invalid class ID", http.StatusBadRequest)
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cR.Cc.ConvertOne(class))
+}
+
+func (cR *ClassResource) DeleteClass(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "class")
+	if !ok {
+		return
+	}
+
+	if err := cR.S.DeleteClass(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cR *ClassResource) EnrollStudent(w http.ResponseWriter, r *http.Request) {
+	cR.changeMember(w, r, "studentId", "student", cR.S.EnrollStudent)
+}
+
+func (cR *ClassResource) UnenrollStudent(w http.ResponseWriter, r *http.Request) {
+	cR.changeMember(w, r, "studentId", "student", cR.S.UnenrollStudent)
+}
+
+func (cR *ClassResource) AssignTeacher(w http.ResponseWriter, r *http.Request) {
+	cR.changeMember(w, r, "teacherId", "teacher", cR.S.AssignTeacher)
+}
+
+func (cR *ClassResource) UnassignTeacher(w http.ResponseWriter, r *http.Request) {
+	cR.changeMember(w, r, "teacherId", "teacher", cR.S.UnassignTeacher)
+}
+
+// changeMember adds or removes the student or teacher in the path to or from
+// the class and returns the class.
+func (cR *ClassResource) changeMember(w http.ResponseWriter, r *http.Request, name string, what string,
+	change func(classID int, memberID int) (entity.Class, error)) {
+	classID, ok := pathID(w, r, "id", "class")
+	if !ok {
+		return
+	}
+	memberID, ok := pathID(w, r, name, what)
+	if !ok {
 		return
 	}
 
-	class := cR.S.GetClass(id)
-	err = json.NewEncoder(w).Encode(class)
+	class, err := change(classID, memberID)
 	if err != nil {
-		fmt.Println(err.Error())
-		w.WriteHeader(http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, cR.Cc.ConvertOne(class))
 }
@@ -0,0 +1,56 @@
+package resursestype
+
+import (
+	"GoLangProjector/hw9/entity"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrClassNotFound),
+		errors.Is(err, entity.ErrStudentNotFound),
+		errors.Is(err, entity.ErrTeacherNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, entity.ErrEmptyName),
+		errors.Is(err, entity.ErrEmptyUsername),
+		errors.Is(err, entity.ErrEmptyPassword),
+		errors.Is(err, entity.ErrPasswordTooLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrUsernameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, entity.ErrInvalidCredentials):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("Failed to encode: %v\n", err)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		fmt.Printf("Failed to decode: %v\n", err)
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, name string, what string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		http.Error(w, "Invalid "+what+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
@@ -2,32 +2,80 @@ package resursestype
 
 import (
 	"GoLangProjector/hw9/converter"
+	"GoLangProjector/hw9/dto"
 	"GoLangProjector/hw9/entity"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"strconv"
 )
 
 type StudentResource struct {
-	S  *entity.Storage
+	S  Storage
 	Sc *converter.StudentConverter
 }
 
+func (sR *StudentResource) GetAllStudents(w http.ResponseWriter, r *http.Request) {
+	students := sR.S.GetAllStudents()
+	dtoStudents := make([]dto.Student, len(students))
+	for i, student := range students {
+		dtoStudents[i] = sR.Sc.Convert(student)
+	}
+	writeJSON(w, http.StatusOK, dtoStudents)
+}
+
 func (sR *StudentResource) GetUserById(w http.ResponseWriter, r *http.Request) {
-	idUser := r.PathValue("id")
-	id, err := strconv.Atoi(idUser)
+	id, ok := pathID(w, r, "id", "student")
+	if !ok {
+		return
+	}
+
+	student, err := sR.S.GetStudent(id)
 	if err != nil {
-		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sR.Sc.Convert(student))
+}
+
+func (sR *StudentResource) CreateStudent(w http.ResponseWriter, r *http.Request) {
+	var newStudent dto.NewStudent
+	if !decode(w, r, &newStudent) {
 		return
 	}
 
-	student := sR.S.GetStudent(id)
-	dtoStudent := sR.Sc.Convert(student)
-	err = json.NewEncoder(w).Encode(dtoStudent)
+	student, err := sR.S.CreateStudent(entity.Student{Name: newStudent.Name, Grades: newStudent.Grades})
 	if err != nil {
-		fmt.Println(err.Error())
-		w.WriteHeader(http.StatusNotFound)
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, sR.Sc.Convert(student))
+}
+
+func (sR *StudentResource) UpdateStudent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "student")
+	if !ok {
+		return
+	}
+	var newStudent dto.NewStudent
+	if !decode(w, r, &newStudent) {
+		return
+	}
+
+	student, err := sR.S.UpdateStudent(id, entity.Student{Name: newStudent.Name, Grades: newStudent.Grades})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sR.Sc.Convert(student))
+}
+
+func (sR *StudentResource) DeleteStudent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "student")
+	if !ok {
+		return
+	}
+
+	if err := sR.S.DeleteStudent(id); err != nil {
+		writeError(w, err)
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
 }
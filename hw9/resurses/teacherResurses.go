@@ -0,0 +1,104 @@
+package resursestype
+
+import (
+	"GoLangProjector/hw9/converter"
+	"GoLangProjector/hw9/dto"
+	"GoLangProjector/hw9/entity"
+	"errors"
+	"net/http"
+)
+
+type TeacherResource struct {
+	S  Storage
+	Tc *converter.TeacherConverter
+}
+
+func (tR *TeacherResource) GetAllTeachers(w http.ResponseWriter, r *http.Request) {
+	teachers := tR.S.GetAllTeachers()
+	dtoTeachers := make([]dto.Teacher, len(teachers))
+	for i, teacher := range teachers {
+		dtoTeachers[i] = tR.Tc.Convert(teacher)
+	}
+	writeJSON(w, http.StatusOK, dtoTeachers)
+}
+
+func (tR *TeacherResource) GetTeacher(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "teacher")
+	if !ok {
+		return
+	}
+
+	teacher, err := tR.S.GetTeacher(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tR.Tc.Convert(teacher))
+}
+
+func (tR *TeacherResource) CreateTeacher(w http.ResponseWriter, r *http.Request) {
+	var newTeacher dto.NewTeacher
+	if !decode(w, r, &newTeacher) {
+		return
+	}
+
+	teacher, err := toTeacher(newTeacher)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	teacher, err = tR.S.CreateTeacher(teacher)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, tR.Tc.Convert(teacher))
+}
+
+func (tR *TeacherResource) UpdateTeacher(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "teacher")
+	if !ok {
+		return
+	}
+	var newTeacher dto.NewTeacher
+	if !decode(w, r, &newTeacher) {
+		return
+	}
+
+	teacher, err := toTeacher(newTeacher)
+	if err != nil && !errors.Is(err, entity.ErrEmptyPassword) {
+		writeError(w, err)
+		return
+	}
+	teacher, err = tR.S.UpdateTeacher(id, teacher)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tR.Tc.Convert(teacher))
+}
+
+func (tR *TeacherResource) DeleteTeacher(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "teacher")
+	if !ok {
+		return
+	}
+
+	if err := tR.S.DeleteTeacher(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toTeacher hashes the password. Without a password it returns the teacher
+// with no hash and ErrEmptyPassword.
+func toTeacher(newTeacher dto.NewTeacher) (entity.Teacher, error) {
+	teacher := entity.Teacher{
+		Name:        newTeacher.Name,
+		TeacherCred: entity.TeacherCred{Username: newTeacher.Username},
+	}
+	hash, err := entity.HashPassword(newTeacher.Password)
+	teacher.TeacherCred.PasswordHash = hash
+	return teacher, err
+}